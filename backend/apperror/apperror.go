@@ -0,0 +1,87 @@
+package apperror
+
+import (
+	"net/http"
+	"time"
+)
+
+// Коды ошибок - часть API: фронтенд ветвится по ним, поэтому однажды
+// выпущенный код не переименовывается
+const (
+	CodeBadRequest           = "bad_request"
+	CodeInvalidJSON          = "invalid_json"
+	CodeValidation           = "validation_failed"
+	CodeUnauthorized         = "unauthorized"
+	CodeForbidden            = "forbidden"
+	CodeNotFound             = "not_found"
+	CodeMethodNotAllowed     = "method_not_allowed"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeRateLimited          = "rate_limited"
+	CodeInternal             = "internal_error"
+)
+
+// Error - ошибка, которую можно показать клиенту: стабильный код, HTTP-статус,
+// сообщение для пользователя и, для ошибок валидации, ошибки отдельных полей
+type Error struct {
+	Code    string
+	Status  int
+	Message string
+	Fields  []FieldError
+	// для ответов 429: через сколько можно повторить запрос
+	RetryAfter time.Duration
+
+	// исходная ошибка, попадает только в лог
+	cause error
+}
+
+// FieldError описывает ошибку в одном поле запроса
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+var (
+	ErrInvalidJSON          = New(http.StatusBadRequest, CodeInvalidJSON, "Неверный формат данных")
+	ErrUnauthorized         = New(http.StatusUnauthorized, CodeUnauthorized, "Требуется авторизация")
+	ErrForbidden            = New(http.StatusForbidden, CodeForbidden, "Недостаточно прав")
+	ErrNotFound             = New(http.StatusNotFound, CodeNotFound, "Ресурс не найден")
+	ErrMethodNotAllowed     = New(http.StatusMethodNotAllowed, CodeMethodNotAllowed, "Метод не разрешён")
+	ErrUnsupportedMediaType = New(http.StatusUnsupportedMediaType, CodeUnsupportedMediaType, "Требуется Content-Type: application/json")
+	ErrRateLimited          = New(http.StatusTooManyRequests, CodeRateLimited, "Слишком много запросов, попробуйте позже")
+	ErrInternal             = New(http.StatusInternalServerError, CodeInternal, "Ошибка сервера")
+)
+
+func New(status int, code, message string) *Error {
+	return &Error{Code: code, Status: status, Message: message}
+}
+
+// Validation - ошибка 400 со списком полей. Сообщение берётся из первого поля.
+func Validation(fields ...FieldError) *Error {
+	e := New(http.StatusBadRequest, CodeValidation, "Проверьте введённые данные")
+	if len(fields) > 0 {
+		e.Message = fields[0].Message
+	}
+	e.Fields = fields
+	return e
+}
+
+// InvalidField - ошибка валидации одного поля или параметра запроса
+func InvalidField(field, code, message string) *Error {
+	return Validation(FieldError{Field: field, Code: code, Message: message})
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// WithCause возвращает копию ошибки с исходной причиной для логов
+func (e *Error) WithCause(err error) *Error {
+	c := *e
+	c.cause = err
+	return &c
+}
@@ -0,0 +1,161 @@
+package apperror
+
+import (
+	"delivery-service/i18n"
+	"delivery-service/repository"
+	"delivery-service/services"
+	"delivery-service/validation"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantFields []string
+	}{
+		{"ошибка сервиса с обёрткой", fmt.Errorf("ошибка при добавлении товара: %w", services.ErrCartFull), http.StatusConflict, "cart_full", nil},
+		{"ошибка с полем", services.ErrWrongCurrentPassword, http.StatusBadRequest, "wrong_current_password", []string{"current_password"}},
+		// текст репозитория английский, код и сообщение берутся у ошибки сервиса
+		{"ошибка репозитория", fmt.Errorf("wrap: %w", repository.ErrUserNotFound), http.StatusNotFound, "user_not_found", nil},
+		{"ошибки валидации", validation.Errors{{Field: "name", Code: "required"}, {Field: "phone", Code: "invalid_phone"}}, http.StatusBadRequest, CodeValidation, []string{"name", "phone"}},
+		{"готовая ошибка API", ErrForbidden, http.StatusForbidden, CodeForbidden, nil},
+		{"неизвестная ошибка", errors.New("pq: connection reset"), http.StatusInternalServerError, CodeInternal, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := From(tt.err)
+			if e.Status != tt.wantStatus || e.Code != tt.wantCode {
+				t.Errorf("From() = %d %s, want %d %s", e.Status, e.Code, tt.wantStatus, tt.wantCode)
+			}
+			var fields []string
+			for _, f := range e.Fields {
+				fields = append(fields, f.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("fields = %v, want %v", fields, tt.wantFields)
+			}
+			// исходная ошибка сохраняется для логов. validation.Errors - срез,
+			// errors.Is его не сравнивает, поэтому причина проверяется и напрямую.
+			if !errors.Is(e, tt.err) && !reflect.DeepEqual(e.Unwrap(), tt.err) {
+				t.Errorf("From() lost cause %v", tt.err)
+			}
+		})
+	}
+}
+
+func TestFromInternalHidesCause(t *testing.T) {
+	e := From(errors.New("pq: password authentication failed"))
+	if e.Message != ErrInternal.Message {
+		t.Errorf("message = %q, want %q", e.Message, ErrInternal.Message)
+	}
+	// WithCause не должен менять общую ErrInternal
+	if ErrInternal.Unwrap() != nil {
+		t.Error("ErrInternal was modified")
+	}
+}
+
+// у каждой ошибки API есть перевод на оба языка
+func TestMappingsLocalized(t *testing.T) {
+	codes := make(map[string]error, len(mappings))
+	for _, m := range mappings {
+		if other, ok := codes[m.code]; ok {
+			t.Errorf("code %q used for %v and %v", m.code, other, m.err)
+		}
+		codes[m.code] = m.err
+		for _, lang := range []i18n.Lang{i18n.RU, i18n.EN} {
+			if _, ok := i18n.Lookup(lang, m.code); !ok {
+				t.Errorf("no %s message for %q", lang, m.code)
+			}
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		acceptLanguage string
+		wantStatus     int
+		wantDetail     string
+		wantRetryAfter string
+	}{
+		{
+			name:           "перевод по Accept-Language",
+			err:            services.ErrCartFull,
+			acceptLanguage: "en-US,en;q=0.9",
+			wantStatus:     http.StatusConflict,
+			wantDetail:     "The cart cannot contain more than 50 items",
+		},
+		{
+			// секунды округляются вверх, чтобы клиент не повторил запрос раньше времени
+			name:           "повтор после блокировки",
+			err:            &services.LoginLockedError{RetryAfter: 1500 * time.Millisecond},
+			acceptLanguage: "ru",
+			wantStatus:     http.StatusTooManyRequests,
+			wantDetail:     "Слишком много неудачных попыток входа, попробуйте позже",
+			wantRetryAfter: "2",
+		},
+		{
+			name:           "внутренняя ошибка",
+			err:            errors.New("pq: connection reset"),
+			acceptLanguage: "ru",
+			wantStatus:     http.StatusInternalServerError,
+			wantDetail:     "Ошибка сервера",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/cart/items", nil)
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+			rec := httptest.NewRecorder()
+			Write(rec, req, tt.err)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Content-Type"); got != ContentType {
+				t.Errorf("Content-Type = %q, want %q", got, ContentType)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantRetryAfter)
+			}
+
+			var problem Problem
+			if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+				t.Fatal(err)
+			}
+			if problem.Status != tt.wantStatus || problem.Detail != tt.wantDetail || problem.Instance != "/api/cart/items" || problem.Type != "about:blank" {
+				t.Errorf("problem = %+v", problem)
+			}
+		})
+	}
+}
+
+func TestWriteValidation(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPut, "/api/profile", nil)
+	req.Header.Set("Accept-Language", "en")
+	rec := httptest.NewRecorder()
+	Write(rec, req, validation.Errors{{Field: "phone", Code: "invalid_phone"}})
+
+	var problem Problem
+	if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+		t.Fatal(err)
+	}
+	if problem.Code != CodeValidation || len(problem.Errors) != 1 || problem.Errors[0].Field != "phone" {
+		t.Fatalf("problem = %+v", problem)
+	}
+	// detail повторяет первую ошибку поля на языке запроса
+	if problem.Detail != problem.Errors[0].Message {
+		t.Errorf("detail = %q, want %q", problem.Detail, problem.Errors[0].Message)
+	}
+}
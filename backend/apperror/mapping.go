@@ -0,0 +1,135 @@
+package apperror
+
+import (
+	"delivery-service/i18n"
+	"delivery-service/repository"
+	"delivery-service/services"
+	"delivery-service/validation"
+	"errors"
+	"net/http"
+)
+
+// mapping сопоставляет ошибки сервисов и репозиториев с ответами API.
+// Сообщение берётся из самой ошибки. Если указано поле, ошибка дублируется
+// в списке errors, чтобы фронтенд мог подсветить это поле.
+type mapping struct {
+	err    error
+	status int
+	code   string
+	field  string
+}
+
+var mappings = []mapping{
+	// пользователи и вход
+	{services.ErrInvalidCredentials, http.StatusUnauthorized, "invalid_credentials", ""},
+	{services.ErrPasswordMismatch, http.StatusBadRequest, "password_mismatch", "confirm_password"},
+	{services.ErrUserExists, http.StatusConflict, "user_exists", ""},
+	{services.ErrInvalidToken, http.StatusUnauthorized, "invalid_token", ""},
+	{services.ErrInvalidEmail, http.StatusBadRequest, "invalid_email", "email"},
+	{services.ErrInvalidPassword, http.StatusBadRequest, "invalid_password", ""},
+	{services.ErrInvalidName, http.StatusBadRequest, "invalid_name", "name"},
+	{services.ErrInvalidRefresh, http.StatusUnauthorized, "invalid_refresh_token", ""},
+	{services.ErrSessionNotFound, http.StatusNotFound, "session_not_found", ""},
+	{services.ErrUserBlocked, http.StatusForbidden, "user_blocked", ""},
+	{services.ErrInvalidChallenge, http.StatusUnauthorized, "invalid_challenge", ""},
+	{services.ErrTooManyLoginAttempts, http.StatusTooManyRequests, "too_many_login_attempts", ""},
+	{services.ErrUserNotFound, http.StatusNotFound, "user_not_found", ""},
+	{services.ErrInvalidInput, http.StatusBadRequest, "invalid_input", ""},
+	{services.ErrInvalidVerificationToken, http.StatusBadRequest, "invalid_verification_token", ""},
+	{services.ErrEmailAlreadyVerified, http.StatusConflict, "email_already_verified", ""},
+	{services.ErrInvalidResetToken, http.StatusBadRequest, "invalid_reset_token", ""},
+	{services.ErrWrongCurrentPassword, http.StatusBadRequest, "wrong_current_password", "current_password"},
+	{services.ErrSamePassword, http.StatusBadRequest, "same_password", "new_password"},
+
+	// двухфакторная аутентификация
+	{services.ErrTwoFactorAlreadyEnabled, http.StatusConflict, "two_factor_already_enabled", ""},
+	{services.ErrTwoFactorNotEnabled, http.StatusBadRequest, "two_factor_not_enabled", ""},
+	{services.ErrTwoFactorSetupRequired, http.StatusBadRequest, "two_factor_setup_required", ""},
+	{services.ErrInvalidTwoFactorCode, http.StatusBadRequest, "invalid_two_factor_code", "code"},
+
+	// администрирование
+	{services.ErrInvalidRole, http.StatusBadRequest, "invalid_role", "role"},
+	{services.ErrCannotModifySelf, http.StatusConflict, "cannot_modify_self", ""},
+	{services.ErrInvalidFilter, http.StatusBadRequest, "invalid_filter", ""},
+
+	// корзина и заказы
+	{services.ErrCartItemNotFound, http.StatusNotFound, "cart_item_not_found", ""},
+	{services.ErrCartFull, http.StatusConflict, "cart_full", ""},
+	{services.ErrCartEmpty, http.StatusConflict, "cart_empty", ""},
+	{services.ErrOrderNotFound, http.StatusNotFound, "order_not_found", ""},
+	{services.ErrEmptyOrder, http.StatusBadRequest, "empty_order", "items"},
+	{services.ErrInvalidOrderItem, http.StatusBadRequest, "invalid_order_item", "items"},
+	{services.ErrInvalidQuantity, http.StatusBadRequest, "invalid_quantity", "quantity"},
+	{services.ErrInvalidDelivery, http.StatusBadRequest, "invalid_delivery", "delivery_details"},
+	{services.ErrInvalidDeliveryDate, http.StatusBadRequest, "invalid_delivery_date", "delivery_details"},
+	{services.ErrTooManyOrderItems, http.StatusBadRequest, "too_many_order_items", "items"},
+	{services.ErrInvalidOrderStatus, http.StatusBadRequest, "invalid_order_status", "status"},
+	{services.ErrStatusTransition, http.StatusConflict, "invalid_status_transition", ""},
+	{services.ErrStatusConflict, http.StatusConflict, "status_conflict", ""},
+	{services.ErrOrderNotCancellable, http.StatusConflict, "order_not_cancellable", ""},
+	{services.ErrInvalidTrackingCode, http.StatusBadRequest, "invalid_tracking_code", ""},
+
+	// пункты выдачи
+	{services.ErrPickupPointNotFound, http.StatusNotFound, "pickup_point_not_found", ""},
+	{services.ErrInvalidPickupPoint, http.StatusBadRequest, "invalid_pickup_point", ""},
+	{services.ErrInvalidCoordinates, http.StatusBadRequest, "invalid_coordinates", ""},
+	{services.ErrInvalidTimezone, http.StatusBadRequest, "invalid_timezone", "timezone"},
+	{services.ErrInvalidWorkingHours, http.StatusBadRequest, "invalid_working_hours", "working_hours"},
+	{services.ErrInvalidCapacity, http.StatusBadRequest, "invalid_capacity", "capacity"},
+	{services.ErrInvalidSearchRadius, http.StatusBadRequest, "invalid_search_radius", "radius"},
+	{services.ErrIncompleteCoordinate, http.StatusBadRequest, "incomplete_coordinates", ""},
+}
+
+// Некоторые сервисы передают ошибки репозиториев без преобразования.
+// Текст у них английский, поэтому клиенту показывается текст ошибки сервиса.
+var repositoryErrors = []struct{ repo, service error }{
+	{repository.ErrUserNotFound, services.ErrUserNotFound},
+	{repository.ErrInvalidInput, services.ErrInvalidInput},
+}
+
+// From приводит любую ошибку к *Error. Неизвестные ошибки становятся
+// ErrInternal, чтобы подробности не попадали к клиенту.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	var fieldErrs validation.Errors
+	if errors.As(err, &fieldErrs) {
+		fields := make([]FieldError, len(fieldErrs))
+		for i, f := range fieldErrs {
+			fields[i] = FieldError{Field: f.Field, Code: f.Code, Message: i18n.T(i18n.Default, f.Code, "field", f.Field)}
+		}
+		return Validation(fields...).WithCause(err)
+	}
+
+	for _, m := range mappings {
+		if !matches(err, m.err) {
+			continue
+		}
+		e := New(m.status, m.code, m.err.Error())
+		if m.field != "" {
+			e.Fields = []FieldError{{Field: m.field, Code: m.code, Message: e.Message}}
+		}
+		var locked *services.LoginLockedError
+		if errors.As(err, &locked) {
+			e.RetryAfter = locked.RetryAfter
+		}
+		return e.WithCause(err)
+	}
+
+	return ErrInternal.WithCause(err)
+}
+
+func matches(err, target error) bool {
+	if errors.Is(err, target) {
+		return true
+	}
+	for _, alias := range repositoryErrors {
+		if alias.service == target && errors.Is(err, alias.repo) {
+			return true
+		}
+	}
+	return false
+}
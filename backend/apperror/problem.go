@@ -0,0 +1,76 @@
+package apperror
+
+import (
+	"delivery-service/i18n"
+	"delivery-service/logging"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+const ContentType = "application/problem+json"
+
+// Problem - тело ответа с ошибкой по RFC 7807. Тип проблемы не публикуется
+// отдельным документом (about:blank), фронтенд ветвится по полю code.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+// Write отправляет ошибку в формате application/problem+json на языке запроса.
+// Ошибки 5xx пишутся в лог вместе с исходной причиной.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	e := From(err)
+	if e.Status >= http.StatusInternalServerError {
+		logging.FromContext(r.Context()).Error("Ошибка при обработке запроса", "error", err)
+	}
+
+	if e.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(e.RetryAfter.Seconds()))))
+	}
+	lang := i18n.FromRequest(r)
+	fields := make([]FieldError, len(e.Fields))
+	for i, f := range e.Fields {
+		f.Message = localize(lang, f.Code, f.Message, "field", f.Field)
+		fields[i] = f
+	}
+	detail := localize(lang, e.Code, e.Message)
+	if e.Code == CodeValidation && len(fields) > 0 {
+		detail = fields[0].Message
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Content-Language", string(lang))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(e.Status)
+
+	problem := Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(e.Status),
+		Status:    e.Status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		Code:      e.Code,
+		Errors:    fields,
+		RequestID: logging.RequestID(r.Context()),
+	}
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		logging.FromContext(r.Context()).Error("Ошибка при отправке ответа", "error", err)
+	}
+}
+
+// localize берёт текст из каталога по коду ошибки. Для кодов, которых нет
+// в каталоге, остаётся сообщение, заданное при создании ошибки.
+func localize(lang i18n.Lang, code, fallback string, args ...string) string {
+	if msg, ok := i18n.Lookup(lang, code, args...); ok {
+		return msg
+	}
+	return fallback
+}
@@ -0,0 +1,25 @@
+package buildinfo
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	defer func(version, commit, buildTime string) {
+		Version, Commit, BuildTime = version, commit, buildTime
+	}(Version, Commit, BuildTime)
+
+	// значения из ldflags важнее сведений VCS
+	Version, Commit, BuildTime = "1.4.0", "abc123", "2024-01-01T12:00:00Z"
+	want := Info{Version: "1.4.0", Commit: "abc123", BuildTime: "2024-01-01T12:00:00Z", GoVersion: runtime.Version()}
+	if got := Get(); got != want {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+
+	// тестовый бинарник собирается без сведений VCS
+	Commit, BuildTime = "", ""
+	if got := Get(); got.Commit != "unknown" || got.BuildTime != "unknown" {
+		t.Errorf("Get() without build info = %+v, want unknown commit and build time", got)
+	}
+}
@@ -0,0 +1,367 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Config - все настройки сервиса. Загружается один раз при запуске
+// и передаётся в сервисы через конструкторы.
+type Config struct {
+	// development или production. Вне development запрещены настройки,
+	// которые годятся только для локальной разработки, например MAILER=log.
+	Env      string         `yaml:"env" env:"APP_ENV"`
+	Server   ServerConfig   `yaml:"server"`
+	Database DatabaseConfig `yaml:"database"`
+	Auth     AuthConfig     `yaml:"auth"`
+	Mail     MailConfig     `yaml:"mail"`
+	Log      LogConfig      `yaml:"log"`
+	Tracing  TracingConfig  `yaml:"tracing"`
+}
+
+type ServerConfig struct {
+	Port           int      `yaml:"port" env:"PORT"`
+	UseHTTPS       bool     `yaml:"use_https" env:"USE_HTTPS"`
+	CertFile       string   `yaml:"cert_file" env:"CERT_FILE"`
+	KeyFile        string   `yaml:"key_file" env:"KEY_FILE"`
+	AllowedOrigins []string `yaml:"allowed_origins" env:"ALLOWED_ORIGINS"`
+	TrustedProxies []string `yaml:"trusted_proxies" env:"TRUSTED_PROXIES"`
+	// ключи партнёров для X-API-Key: у каждого свой лимит на отслеживание
+	PartnerAPIKeys []string `yaml:"partner_api_keys" env:"PARTNER_API_KEYS"`
+	// если задан, /metrics требует заголовок Authorization: Bearer <token>
+	MetricsToken string `yaml:"metrics_token" env:"METRICS_TOKEN"`
+	// адрес фронтенда, от него строятся ссылки в письмах
+	AppURL string `yaml:"app_url" env:"APP_URL"`
+
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	// сколько ждать завершения начатых запросов и фоновых задач при остановке
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	// пауза между переходом /readyz в 503 и закрытием приёма соединений,
+	// чтобы балансировщик успел исключить экземпляр
+	ShutdownDelay time.Duration `yaml:"shutdown_delay" env:"SHUTDOWN_DELAY"`
+}
+
+// DatabaseConfig - либо URL целиком, либо отдельные параметры подключения
+type DatabaseConfig struct {
+	URL      string `yaml:"url" env:"DATABASE_URL"`
+	Host     string `yaml:"host" env:"DB_HOST"`
+	Port     int    `yaml:"port" env:"DB_PORT"`
+	User     string `yaml:"user" env:"DB_USER"`
+	Password string `yaml:"password" env:"DB_PASSWORD"`
+	Name     string `yaml:"name" env:"DB_NAME"`
+}
+
+type AuthConfig struct {
+	JWTSecret            string   `yaml:"jwt_secret" env:"JWT_SECRET"`
+	RequireVerifiedEmail bool     `yaml:"require_verified_email" env:"REQUIRE_VERIFIED_EMAIL"`
+	TOTPIssuer           string   `yaml:"totp_issuer" env:"TOTP_ISSUER"`
+	AdminEmails          []string `yaml:"admin_emails" env:"ADMIN_EMAILS"`
+	// memory или postgres; postgres нужен, если запущено несколько экземпляров
+	LoginAttemptsStore string `yaml:"login_attempts_store" env:"LOGIN_ATTEMPTS_STORE"`
+}
+
+type MailConfig struct {
+	// log, file или smtp
+	Driver       string `yaml:"driver" env:"MAILER"`
+	Dir          string `yaml:"dir" env:"MAIL_DIR"`
+	SMTPHost     string `yaml:"smtp_host" env:"SMTP_HOST"`
+	SMTPPort     int    `yaml:"smtp_port" env:"SMTP_PORT"`
+	SMTPUser     string `yaml:"smtp_user" env:"SMTP_USER"`
+	SMTPPassword string `yaml:"smtp_password" env:"SMTP_PASSWORD"`
+	SMTPFrom     string `yaml:"smtp_from" env:"SMTP_FROM"`
+}
+
+type LogConfig struct {
+	// debug, info, warn или error
+	Level string `yaml:"level" env:"LOG_LEVEL"`
+	// json или text
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+	// что скрывать в логах: email, phone, token; none - ничего
+	Redact []string `yaml:"redact" env:"LOG_REDACT"`
+}
+
+type TracingConfig struct {
+	// none, stdout (для локальной отладки) или otlp
+	Exporter string `yaml:"exporter" env:"TRACING_EXPORTER"`
+	// адрес OTLP/HTTP коллектора, например http://otel-collector:4318
+	OTLPEndpoint string `yaml:"otlp_endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	ServiceName  string `yaml:"service_name" env:"OTEL_SERVICE_NAME"`
+	// доля трассируемых запросов без родительского span, от 0 до 1
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+}
+
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+
+	LoginAttemptsMemory   = "memory"
+	LoginAttemptsPostgres = "postgres"
+
+	MailerLog  = "log"
+	MailerFile = "file"
+	MailerSMTP = "smtp"
+
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+
+	TracingNone   = "none"
+	TracingStdout = "stdout"
+	TracingOTLP   = "otlp"
+
+	defaultConfigFile = "config.yaml"
+)
+
+func defaults() *Config {
+	return &Config{
+		Env: EnvProduction,
+		Server: ServerConfig{
+			Port:           8080,
+			AllowedOrigins: []string{"http://localhost:3000", "https://practice-2025.vercel.app"},
+			// прокси Next.js на той же машине
+			TrustedProxies: []string{"127.0.0.1", "::1"},
+			AppURL:         "http://localhost:3000",
+
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+			ShutdownTimeout:   20 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Port: 5432,
+		},
+		Auth: AuthConfig{
+			TOTPIssuer:         "Delivery Service",
+			LoginAttemptsStore: LoginAttemptsMemory,
+		},
+		Mail: MailConfig{
+			SMTPPort: 587,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: LogFormatJSON,
+			Redact: []string{"email", "phone", "token"},
+		},
+		Tracing: TracingConfig{
+			Exporter:     TracingNone,
+			OTLPEndpoint: "http://localhost:4318",
+			ServiceName:  "delivery-service",
+			SampleRatio:  1,
+		},
+	}
+}
+
+// Load собирает настройки по возрастанию приоритета: значения по умолчанию,
+// YAML-файл (CONFIG_FILE или config.yaml, если есть), .env, переменные окружения.
+// Для любой переменной X значение можно передать файлом через X_FILE.
+func Load() (*Config, error) {
+	if err := godotenv.Load(); err != nil {
+		slog.Warn(".env не найден, используются переменные окружения")
+	}
+
+	cfg := defaults()
+
+	path, required := os.Getenv("CONFIG_FILE"), true
+	if path == "" {
+		path, required = defaultConfigFile, false
+	}
+	if err := loadYAML(cfg, path, required); err != nil {
+		return nil, err
+	}
+
+	if err := applyEnv(cfg, os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	cfg.normalize()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func loadYAML(cfg *Config, path string, required bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return nil
+		}
+		return fmt.Errorf("ошибка чтения файла конфигурации %s: %v", path, err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("ошибка разбора файла конфигурации %s: %v", path, err)
+	}
+	return nil
+}
+
+func (c *Config) normalize() {
+	c.Server.AppURL = strings.TrimRight(strings.TrimSpace(c.Server.AppURL), "/")
+	c.Auth.LoginAttemptsStore = strings.ToLower(strings.TrimSpace(c.Auth.LoginAttemptsStore))
+	c.Env = strings.ToLower(strings.TrimSpace(c.Env))
+	c.Mail.Driver = strings.ToLower(strings.TrimSpace(c.Mail.Driver))
+	if c.Mail.Driver == "" && c.Env == EnvDevelopment {
+		c.Mail.Driver = MailerLog
+	}
+	for i, email := range c.Auth.AdminEmails {
+		c.Auth.AdminEmails[i] = strings.ToLower(email)
+	}
+	c.Log.Level = strings.ToLower(strings.TrimSpace(c.Log.Level))
+	c.Log.Format = strings.ToLower(strings.TrimSpace(c.Log.Format))
+	for i, category := range c.Log.Redact {
+		c.Log.Redact[i] = strings.ToLower(strings.TrimSpace(category))
+	}
+	if len(c.Log.Redact) == 1 && c.Log.Redact[0] == "none" {
+		c.Log.Redact = nil
+	}
+	c.Tracing.Exporter = strings.ToLower(strings.TrimSpace(c.Tracing.Exporter))
+	if c.Tracing.Exporter == "" {
+		c.Tracing.Exporter = TracingNone
+	}
+}
+
+// Validate проверяет все значения сразу и возвращает полный список ошибок
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Env != EnvDevelopment && c.Env != EnvProduction {
+		fail("APP_ENV: допустимо %q или %q, получено %q", EnvDevelopment, EnvProduction, c.Env)
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		fail("PORT: ожидается число от 1 до 65535, получено %d", c.Server.Port)
+	}
+	timeouts := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"HTTP_READ_TIMEOUT", c.Server.ReadTimeout},
+		{"HTTP_READ_HEADER_TIMEOUT", c.Server.ReadHeaderTimeout},
+		{"HTTP_WRITE_TIMEOUT", c.Server.WriteTimeout},
+		{"HTTP_IDLE_TIMEOUT", c.Server.IdleTimeout},
+		{"SHUTDOWN_TIMEOUT", c.Server.ShutdownTimeout},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value <= 0 {
+			fail("%s: должно быть больше нуля, получено %s", timeout.name, timeout.value)
+		}
+	}
+	if c.Server.ShutdownDelay < 0 {
+		fail("SHUTDOWN_DELAY: не может быть отрицательным, получено %s", c.Server.ShutdownDelay)
+	}
+	if c.Server.UseHTTPS && (c.Server.CertFile == "" || c.Server.KeyFile == "") {
+		fail("USE_HTTPS: нужно задать CERT_FILE и KEY_FILE")
+	}
+	if u, err := url.Parse(c.Server.AppURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		fail("APP_URL: ожидается абсолютный адрес http(s), получено %q", c.Server.AppURL)
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			fail("TRUSTED_PROXIES: %q не является IP-адресом или подсетью CIDR", proxy)
+		}
+	}
+
+	if c.Database.URL == "" {
+		if c.Database.Host == "" {
+			fail("DB_HOST: обязателен, если не задан DATABASE_URL")
+		}
+		if c.Database.User == "" {
+			fail("DB_USER: обязателен, если не задан DATABASE_URL")
+		}
+		if c.Database.Name == "" {
+			fail("DB_NAME: обязателен, если не задан DATABASE_URL")
+		}
+		if c.Database.Port < 1 || c.Database.Port > 65535 {
+			fail("DB_PORT: ожидается число от 1 до 65535, получено %d", c.Database.Port)
+		}
+	} else if _, err := url.Parse(c.Database.URL); err != nil {
+		fail("DATABASE_URL: некорректный адрес")
+	}
+
+	if c.Auth.JWTSecret == "" {
+		fail("JWT_SECRET: обязателен")
+	}
+	switch c.Auth.LoginAttemptsStore {
+	case LoginAttemptsMemory, LoginAttemptsPostgres:
+	default:
+		fail("LOGIN_ATTEMPTS_STORE: допустимо %q или %q, получено %q", LoginAttemptsMemory, LoginAttemptsPostgres, c.Auth.LoginAttemptsStore)
+	}
+
+	switch c.Mail.Driver {
+	case MailerLog:
+		if c.Env != EnvDevelopment {
+			fail("MAILER: log не отправляет письма и допустим только при APP_ENV=%s", EnvDevelopment)
+		}
+	case MailerFile:
+	case MailerSMTP:
+		if c.Mail.SMTPHost == "" {
+			fail("SMTP_HOST: обязателен при MAILER=smtp")
+		}
+		if c.Mail.SMTPFrom == "" {
+			fail("SMTP_FROM: обязателен при MAILER=smtp")
+		}
+		if c.Mail.SMTPPort < 1 || c.Mail.SMTPPort > 65535 {
+			fail("SMTP_PORT: ожидается число от 1 до 65535, получено %d", c.Mail.SMTPPort)
+		}
+	case "":
+		fail("MAILER: обязателен вне %s, укажите smtp или file", EnvDevelopment)
+	default:
+		fail("MAILER: допустимо log, file или smtp, получено %q", c.Mail.Driver)
+	}
+
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		fail("LOG_LEVEL: допустимо debug, info, warn или error, получено %q", c.Log.Level)
+	}
+	if c.Log.Format != LogFormatJSON && c.Log.Format != LogFormatText {
+		fail("LOG_FORMAT: допустимо %q или %q, получено %q", LogFormatJSON, LogFormatText, c.Log.Format)
+	}
+	for _, category := range c.Log.Redact {
+		switch category {
+		case "email", "phone", "token":
+		default:
+			fail("LOG_REDACT: неизвестная категория %q, допустимо email, phone, token или none", category)
+		}
+	}
+
+	switch c.Tracing.Exporter {
+	case TracingNone, TracingStdout:
+	case TracingOTLP:
+		if u, err := url.Parse(c.Tracing.OTLPEndpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fail("OTEL_EXPORTER_OTLP_ENDPOINT: ожидается абсолютный адрес http(s), получено %q", c.Tracing.OTLPEndpoint)
+		}
+	default:
+		fail("TRACING_EXPORTER: допустимо none, stdout или otlp, получено %q", c.Tracing.Exporter)
+	}
+	if c.Tracing.ServiceName == "" {
+		fail("OTEL_SERVICE_NAME: обязателен")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		fail("TRACING_SAMPLE_RATIO: ожидается число от 0 до 1, получено %g", c.Tracing.SampleRatio)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("некорректная конфигурация:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type lookupFunc func(name string) (string, bool)
+
+// applyEnv заполняет поля с тегом env значениями переменных окружения.
+// Если задана переменная NAME_FILE, значение читается из указанного файла
+// (так передаются секреты в Docker и Kubernetes).
+func applyEnv(cfg *Config, lookup lookupFunc) error {
+	var errs []error
+	walkEnvFields(reflect.ValueOf(cfg).Elem(), func(name string, field reflect.Value) {
+		value, ok, err := lookupValue(name, lookup)
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		if !ok {
+			return
+		}
+		// пустое значение, как и раньше с os.Getenv, означает «не задано»,
+		// кроме списков: TRUSTED_PROXIES= явно очищает список по умолчанию
+		if strings.TrimSpace(value) == "" && field.Kind() != reflect.Slice {
+			return
+		}
+		if err := setField(field, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", name, err))
+		}
+	})
+	if len(errs) > 0 {
+		return fmt.Errorf("некорректная конфигурация:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func walkEnvFields(v reflect.Value, fn func(name string, field reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			walkEnvFields(field, fn)
+			continue
+		}
+		if name := t.Field(i).Tag.Get("env"); name != "" {
+			fn(name, field)
+		}
+	}
+}
+
+func lookupValue(name string, lookup lookupFunc) (string, bool, error) {
+	value, ok := lookup(name)
+	path, fromFile := lookup(name + "_FILE")
+	if !fromFile || path == "" {
+		return value, ok, nil
+	}
+	if ok && value != "" {
+		return "", false, fmt.Errorf("%s и %s_FILE заданы одновременно", name, name)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("%s_FILE: ошибка чтения файла: %v", name, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), true, nil
+}
+
+// setField вызывается для непустых значений и для списков, см. applyEnv
+func setField(field reflect.Value, value string) error {
+	value = strings.TrimSpace(value)
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("ожидается длительность, например 30s или 2m, получено %q", value)
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("ожидается true или false, получено %q", value)
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("ожидается число, получено %q", value)
+		}
+		field.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("ожидается число, получено %q", value)
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		field.Set(reflect.ValueOf(splitList(value)))
+	default:
+		return fmt.Errorf("неподдерживаемый тип поля %s", field.Type())
+	}
+	return nil
+}
+
+// splitList разбирает список через запятую, пустые элементы отбрасываются
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func envLookup(env map[string]string) lookupFunc {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+func TestApplyEnvFilePrecedence(t *testing.T) {
+	secret := writeFile(t, "jwt_secret", "from-file\n")
+	empty := writeFile(t, "empty", "")
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    string
+		wantErr string
+	}{
+		{"значение по умолчанию", map[string]string{}, "default", ""},
+		{"переменная", map[string]string{"JWT_SECRET": "from-env"}, "from-env", ""},
+		{"пустая переменная не задана", map[string]string{"JWT_SECRET": "  "}, "default", ""},
+		{"файл без завершающего перевода строки", map[string]string{"JWT_SECRET_FILE": secret}, "from-file", ""},
+		{"пустая переменная и файл", map[string]string{"JWT_SECRET": "", "JWT_SECRET_FILE": secret}, "from-file", ""},
+		{"пустой путь к файлу", map[string]string{"JWT_SECRET": "from-env", "JWT_SECRET_FILE": ""}, "from-env", ""},
+		{"пустой файл не задаёт значение", map[string]string{"JWT_SECRET_FILE": empty}, "default", ""},
+		{"переменная и файл одновременно", map[string]string{"JWT_SECRET": "from-env", "JWT_SECRET_FILE": secret}, "", "JWT_SECRET и JWT_SECRET_FILE заданы одновременно"},
+		{"файла нет", map[string]string{"JWT_SECRET_FILE": filepath.Join(t.TempDir(), "missing")}, "", "JWT_SECRET_FILE:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaults()
+			cfg.Auth.JWTSecret = "default"
+
+			err := applyEnv(cfg, envLookup(tt.env))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("applyEnv() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyEnv() error = %v", err)
+			}
+			if cfg.Auth.JWTSecret != tt.want {
+				t.Errorf("JWTSecret = %q, want %q", cfg.Auth.JWTSecret, tt.want)
+			}
+		})
+	}
+}
+
+// YAML перекрывает значения по умолчанию, переменные окружения - YAML
+func TestLoadSourcesPrecedence(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+server:
+  port: 9000
+  allowed_origins: [https://yaml.example]
+  read_timeout: 1m
+auth:
+  jwt_secret: from-yaml
+log:
+  level: debug
+`)
+
+	cfg := defaults()
+	if err := loadYAML(cfg, path, true); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{
+		"PORT":            "8081",
+		"ALLOWED_ORIGINS": "https://a.example, ,https://b.example",
+		"LOG_LEVEL":       "",
+	}
+	if err := applyEnv(cfg, envLookup(env)); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Server.Port != 8081 {
+		t.Errorf("Port = %d, want env value 8081", cfg.Server.Port)
+	}
+	if want := []string{"https://a.example", "https://b.example"}; !reflect.DeepEqual(cfg.Server.AllowedOrigins, want) {
+		t.Errorf("AllowedOrigins = %v, want %v", cfg.Server.AllowedOrigins, want)
+	}
+	if cfg.Server.ReadTimeout != time.Minute {
+		t.Errorf("ReadTimeout = %s, want YAML value 1m", cfg.Server.ReadTimeout)
+	}
+	if cfg.Auth.JWTSecret != "from-yaml" {
+		t.Errorf("JWTSecret = %q, want YAML value", cfg.Auth.JWTSecret)
+	}
+	if cfg.Log.Level != "debug" {
+		t.Errorf("Log.Level = %q, want YAML value kept over empty env", cfg.Log.Level)
+	}
+	if cfg.Server.WriteTimeout != 30*time.Second {
+		t.Errorf("WriteTimeout = %s, want default 30s", cfg.Server.WriteTimeout)
+	}
+}
+
+func TestLoadYAML(t *testing.T) {
+	if err := loadYAML(defaults(), filepath.Join(t.TempDir(), "missing.yaml"), false); err != nil {
+		t.Errorf("optional missing file: %v", err)
+	}
+	if err := loadYAML(defaults(), filepath.Join(t.TempDir(), "missing.yaml"), true); err == nil {
+		t.Error("required missing file accepted")
+	}
+	if err := loadYAML(defaults(), writeFile(t, "empty.yaml", ""), true); err != nil {
+		t.Errorf("empty file: %v", err)
+	}
+	if err := loadYAML(defaults(), writeFile(t, "typo.yaml", "server:\n  prot: 80\n"), true); err == nil {
+		t.Error("unknown field accepted")
+	}
+}
+
+func TestApplyEnvInvalidValues(t *testing.T) {
+	tests := []struct {
+		name, value string
+	}{
+		{"PORT", "eighty"},
+		{"USE_HTTPS", "yes please"},
+		{"HTTP_READ_TIMEOUT", "15"},
+		{"TRACING_SAMPLE_RATIO", "half"},
+	}
+	for _, tt := range tests {
+		err := applyEnv(defaults(), envLookup(map[string]string{tt.name: tt.value}))
+		if err == nil || !strings.Contains(err.Error(), tt.name+":") {
+			t.Errorf("%s=%q: error = %v", tt.name, tt.value, err)
+		}
+	}
+}
+
+// пустая переменная очищает список, а не оставляет значение по умолчанию
+func TestApplyEnvEmptyList(t *testing.T) {
+	cfg := defaults()
+	cfg.Server.AllowedOrigins = []string{"https://default.example"}
+	if len(cfg.Server.TrustedProxies) == 0 {
+		t.Fatal("TrustedProxies has no default")
+	}
+
+	env := map[string]string{"TRUSTED_PROXIES": "", "ALLOWED_ORIGINS": " "}
+	if err := applyEnv(cfg, envLookup(env)); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.TrustedProxies == nil || len(cfg.Server.TrustedProxies) != 0 {
+		t.Errorf("TrustedProxies = %#v, want empty", cfg.Server.TrustedProxies)
+	}
+	if len(cfg.Server.AllowedOrigins) != 0 {
+		t.Errorf("AllowedOrigins = %v, want empty", cfg.Server.AllowedOrigins)
+	}
+
+	// без переменной остаётся значение по умолчанию
+	cfg = defaults()
+	if err := applyEnv(cfg, envLookup(map[string]string{})); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Server.TrustedProxies) == 0 {
+		t.Error("TrustedProxies default lost without env")
+	}
+}
@@ -2,19 +2,19 @@ package db
 
 import (
 	"database/sql"
+	"delivery-service/config"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
 
 	_ "github.com/lib/pq"
 )
 
 var DB *sql.DB
 
-func InitDB() error {
-	log.Println("Инициализация базы данных...")
+func InitDB(cfg config.DatabaseConfig) error {
+	slog.Info("Инициализация базы данных")
 
-	dbURL := os.Getenv("DATABASE_URL")
+	dbURL := cfg.URL
 	var connStr string
 
 	if dbURL != "" {
@@ -25,16 +25,16 @@ func InitDB() error {
 		connStr = dbURL
 	} else {
 		connStr = fmt.Sprintf(
-			"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-			os.Getenv("DB_HOST"),
-			os.Getenv("DB_PORT"),
-			os.Getenv("DB_USER"),
-			os.Getenv("DB_PASSWORD"),
-			os.Getenv("DB_NAME"),
+			"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+			cfg.Host,
+			cfg.Port,
+			cfg.User,
+			cfg.Password,
+			cfg.Name,
 		)
 	}
 
-	log.Printf("Подключение к базе данных: %s:%s/%s", os.Getenv("DB_HOST"), os.Getenv("DB_PORT"), os.Getenv("DB_NAME"))
+	slog.Info("Подключение к базе данных", "host", cfg.Host, "port", cfg.Port, "name", cfg.Name)
 
 	var err error
 	DB, err = sql.Open("postgres", connStr)
@@ -46,27 +46,7 @@ func InitDB() error {
 		return fmt.Errorf("error connecting to the database: %v", err)
 	}
 
-	log.Println("Успешное подключение к базе данных")
-
-	// Создаем таблицы
-	schema, err := os.ReadFile("db/schema.sql")
-	if err != nil {
-		log.Printf("Ошибка чтения схемы базы данных: %v", err)
-		// Попробуем прочитать из текущей директории
-		schema, err = os.ReadFile("schema.sql")
-		if err != nil {
-			return fmt.Errorf("error reading schema file: %v", err)
-		}
-	}
-
-	log.Println("Создание таблиц...")
-
-	_, err = DB.Exec(string(schema))
-	if err != nil {
-		return fmt.Errorf("error creating tables: %v", err)
-	}
-
-	log.Println("Таблицы успешно созданы")
+	slog.Info("Успешное подключение к базе данных")
 
 	return nil
 }
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// PingCheck проверяет, что база данных принимает соединения
+type PingCheck struct {
+	db *sql.DB
+}
+
+func NewPingCheck(db *sql.DB) *PingCheck {
+	if db == nil {
+		panic("database connection is required")
+	}
+	return &PingCheck{db: db}
+}
+
+func (c *PingCheck) Name() string {
+	return "database"
+}
+
+func (c *PingCheck) Check(ctx context.Context) error {
+	return c.db.PingContext(ctx)
+}
+
+// MigrationCheck не пускает трафик на экземпляр, если схема базы отстаёт
+// от миграций, встроенных в бинарник
+type MigrationCheck struct {
+	migrator *Migrator
+}
+
+func NewMigrationCheck(migrator *Migrator) *MigrationCheck {
+	if migrator == nil {
+		panic("migrator is required")
+	}
+	return &MigrationCheck{migrator: migrator}
+}
+
+func (c *MigrationCheck) Name() string {
+	return "migrations"
+}
+
+func (c *MigrationCheck) Check(ctx context.Context) error {
+	pending, err := c.migrator.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if pending > 0 {
+		return fmt.Errorf("%d pending migrations", pending)
+	}
+	return nil
+}
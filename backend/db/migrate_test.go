@@ -0,0 +1,98 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// Встроенные миграции: версии идут подряд, у каждой есть откат
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+	for i, migration := range migrations {
+		if migration.Version != int64(i+1) {
+			t.Errorf("migration %d_%s: want version %d", migration.Version, migration.Name, i+1)
+		}
+		if strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("migration %d_%s has no down script", migration.Version, migration.Name)
+		}
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	file := func(content string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(content)} }
+
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "сортировка по версии",
+			files: fstest.MapFS{
+				"migrations/0010_b.up.sql":   file("B"),
+				"migrations/0002_a.up.sql":   file("A"),
+				"migrations/0002_a.down.sql": file("-A"),
+				"migrations/README.md":       file("не миграция"),
+			},
+			want: []string{"2_a", "10_b"},
+		},
+		{
+			name:    "нет up",
+			files:   fstest.MapFS{"migrations/0001_a.down.sql": file("-A")},
+			wantErr: "has no up file",
+		},
+		{
+			name: "одна версия у разных миграций",
+			files: fstest.MapFS{
+				"migrations/0001_a.up.sql": file("A"),
+				"migrations/0001_b.up.sql": file("B"),
+			},
+			wantErr: "duplicate migration version 1",
+		},
+		{
+			name:    "нет имени",
+			files:   fstest.MapFS{"migrations/0001.up.sql": file("A")},
+			wantErr: "invalid migration file name",
+		},
+		{
+			name:    "версия не число",
+			files:   fstest.MapFS{"migrations/first_a.up.sql": file("A")},
+			wantErr: "invalid migration version",
+		},
+		{
+			name:    "нулевая версия",
+			files:   fstest.MapFS{"migrations/0000_a.up.sql": file("A")},
+			wantErr: "invalid migration version",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := loadMigrations(tt.files)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("loadMigrations() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, len(migrations))
+			for i, m := range migrations {
+				got[i] = fmt.Sprintf("%d_%s", m.Version, m.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("migrations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS orders (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(50) NOT NULL DEFAULT 'created',
+    delivery_address TEXT NOT NULL,
+    delivery_date DATE NOT NULL,
+    delivery_time VARCHAR(5) NOT NULL,
+    delivery_notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_user_id ON orders(user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    marketplace VARCHAR(100) NOT NULL,
+    link TEXT NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    size VARCHAR(50),
+    color VARCHAR(50),
+    notes TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_items_order_id ON order_items(order_id);
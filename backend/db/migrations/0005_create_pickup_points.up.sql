@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS pickup_points (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(200) NOT NULL,
+    address TEXT NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    latitude DOUBLE PRECISION NOT NULL CHECK (latitude BETWEEN -90 AND 90),
+    longitude DOUBLE PRECISION NOT NULL CHECK (longitude BETWEEN -180 AND 180),
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Europe/Moscow',
+    working_hours JSONB NOT NULL DEFAULT '{}',
+    phone VARCHAR(20),
+    capacity INTEGER NOT NULL DEFAULT 0 CHECK (capacity >= 0),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_pickup_points_location ON pickup_points(latitude, longitude) WHERE is_active;
@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS blocked_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS blocked_reason TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users(created_at DESC);
@@ -0,0 +1,19 @@
+-- Неудачные попытки входа и временные блокировки по IP и учётной записи.
+-- Нужны, когда несколько экземпляров сервиса должны видеть общие счётчики.
+CREATE TABLE IF NOT EXISTS login_failures (
+    id BIGSERIAL PRIMARY KEY,
+    key VARCHAR(128) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_failures_key ON login_failures(key, created_at);
+CREATE INDEX IF NOT EXISTS idx_login_failures_created_at ON login_failures(created_at);
+
+CREATE TABLE IF NOT EXISTS login_lockouts (
+    key VARCHAR(128) PRIMARY KEY,
+    lockouts INTEGER NOT NULL,
+    locked_until TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_lockouts_updated_at ON login_lockouts(updated_at);
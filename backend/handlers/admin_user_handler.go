@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"delivery-service/apperror"
+	"delivery-service/middleware"
+	"delivery-service/models"
+	"delivery-service/services"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type AdminUserHandler struct {
+	adminUserService *services.AdminUserService
+	passwordService  *services.PasswordService
+}
+
+func NewAdminUserHandler(adminUserService *services.AdminUserService, passwordService *services.PasswordService) *AdminUserHandler {
+	if adminUserService == nil || passwordService == nil {
+		panic("admin user service and password service are required")
+	}
+	return &AdminUserHandler{adminUserService: adminUserService, passwordService: passwordService}
+}
+
+// ListUsers - поиск пользователей. Параметры: email, name, city, role,
+// blocked, created_from и created_to (YYYY-MM-DD, включительно), page, per_page.
+func (h *AdminUserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	filter := models.UserFilter{
+		Email: params.Get("email"),
+		Name:  params.Get("name"),
+		City:  params.Get("city"),
+		Role:  params.Get("role"),
+	}
+
+	if blocked := params.Get("blocked"); blocked != "" {
+		value, err := strconv.ParseBool(blocked)
+		if err != nil {
+			apperror.Write(w, r, apperror.InvalidField("blocked", "invalid_boolean", "Некорректный параметр blocked"))
+			return
+		}
+		filter.Blocked = &value
+	}
+
+	var err error
+	if filter.CreatedFrom, err = parseOptionalDate(params.Get("created_from")); err != nil {
+		apperror.Write(w, r, apperror.InvalidField("created_from", "invalid_date", "Некорректная дата created_from (требуется YYYY-MM-DD)"))
+		return
+	}
+	if filter.CreatedTo, err = parseOptionalDate(params.Get("created_to")); err != nil {
+		apperror.Write(w, r, apperror.InvalidField("created_to", "invalid_date", "Некорректная дата created_to (требуется YYYY-MM-DD)"))
+		return
+	}
+	if filter.CreatedTo != nil {
+		// граница включительно: берём всё до начала следующего дня
+		next := filter.CreatedTo.AddDate(0, 0, 1)
+		filter.CreatedTo = &next
+	}
+
+	page, perPage := 0, 0
+	if value := params.Get("page"); value != "" {
+		if page, err = strconv.Atoi(value); err != nil {
+			apperror.Write(w, r, apperror.InvalidField("page", "invalid_number", "Некорректный номер страницы"))
+			return
+		}
+	}
+	if value := params.Get("per_page"); value != "" {
+		if perPage, err = strconv.Atoi(value); err != nil {
+			apperror.Write(w, r, apperror.InvalidField("per_page", "invalid_number", "Некорректный per_page"))
+			return
+		}
+	}
+
+	result, err := h.adminUserService.ListUsers(r.Context(), &filter, page, perPage)
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusOK, result)
+}
+
+func (h *AdminUserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := parseUserID(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.adminUserService.GetUser(r.Context(), userID)
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusOK, user)
+}
+
+func (h *AdminUserHandler) BlockUser(w http.ResponseWriter, r *http.Request) {
+	actorID, _ := r.Context().Value("userID").(int64)
+	userID, ok := parseUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.BlockUserRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			apperror.Write(w, r, apperror.ErrInvalidJSON)
+			return
+		}
+	}
+
+	user, err := h.adminUserService.BlockUser(r.Context(), actorID, userID, &req, clientInfo(r))
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusOK, user)
+}
+
+func (h *AdminUserHandler) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	actorID, _ := r.Context().Value("userID").(int64)
+	userID, ok := parseUserID(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.adminUserService.UnblockUser(r.Context(), actorID, userID, clientInfo(r))
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusOK, user)
+}
+
+func (h *AdminUserHandler) ChangeRole(w http.ResponseWriter, r *http.Request) {
+	actorID, _ := r.Context().Value("userID").(int64)
+	userID, ok := parseUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.ChangeRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.Write(w, r, apperror.ErrInvalidJSON)
+		return
+	}
+
+	user, err := h.adminUserService.ChangeRole(r.Context(), actorID, userID, &req, clientInfo(r))
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusOK, user)
+}
+
+func (h *AdminUserHandler) ForcePasswordReset(w http.ResponseWriter, r *http.Request) {
+	actorID, _ := r.Context().Value("userID").(int64)
+	userID, ok := parseUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.passwordService.ForcePasswordReset(r.Context(), actorID, userID, clientInfo(r)); err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func parseUserID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	userID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || userID <= 0 {
+		apperror.Write(w, r, apperror.InvalidField("id", "invalid_id", "Некорректный ID пользователя"))
+		return 0, false
+	}
+	return userID, true
+}
+
+func parseOptionalDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
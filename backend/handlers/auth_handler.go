@@ -1,98 +1,188 @@
 package handlers
 
 import (
+	"delivery-service/apperror"
+	"delivery-service/logging"
 	"delivery-service/middleware"
 	"delivery-service/models"
 	"delivery-service/services"
 	"encoding/json"
-	"fmt"
-	"log"
+	"errors"
+	"io"
 	"net/http"
 )
 
 type AuthHandler struct {
-	authService *services.AuthService
+	authService         *services.AuthService
+	verificationService *services.EmailVerificationService
 }
 
-func NewAuthHandler(authService *services.AuthService) *AuthHandler {
-	return &AuthHandler{authService: authService}
+func NewAuthHandler(authService *services.AuthService, verificationService *services.EmailVerificationService) *AuthHandler {
+	return &AuthHandler{authService: authService, verificationService: verificationService}
 }
 
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
-	log.Printf("Получен запрос на регистрацию от %s", r.RemoteAddr)
-
 	// Проверяем метод запроса
 	if r.Method != http.MethodPost {
-		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		apperror.Write(w, r, apperror.ErrMethodNotAllowed)
 		return
 	}
 
 	// Проверяем Content-Type
 	contentType := r.Header.Get("Content-Type")
 	if contentType != "application/json" {
-		http.Error(w, "Требуется Content-Type: application/json", http.StatusUnsupportedMediaType)
+		apperror.Write(w, r, apperror.ErrUnsupportedMediaType)
 		return
 	}
 
 	var req models.RegisterRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Printf("Ошибка декодирования JSON: %v", err)
-		http.Error(w, "Неверный формат данных", http.StatusBadRequest)
+		logging.FromContext(r.Context()).Warn("Ошибка декодирования JSON", "error", err)
+		apperror.Write(w, r, apperror.ErrInvalidJSON)
 		return
 	}
 
 	// Валидация данных
-	if req.Name == "" || req.Email == "" || req.Password == "" || req.ConfirmPassword == "" {
-		http.Error(w, "Все поля обязательны для заполнения", http.StatusBadRequest)
+	var missing []apperror.FieldError
+	for _, field := range []struct{ name, value string }{
+		{"name", req.Name},
+		{"email", req.Email},
+		{"password", req.Password},
+		{"confirm_password", req.ConfirmPassword},
+	} {
+		if field.value == "" {
+			missing = append(missing, apperror.FieldError{Field: field.name, Code: "required", Message: "Все поля обязательны для заполнения"})
+		}
+	}
+	if len(missing) > 0 {
+		apperror.Write(w, r, apperror.Validation(missing...))
 		return
 	}
 
 	if req.Password != req.ConfirmPassword {
-		http.Error(w, "Пароли не совпадают", http.StatusBadRequest)
+		apperror.Write(w, r, services.ErrPasswordMismatch)
 		return
 	}
 
-	log.Printf("Попытка регистрации пользователя: %s", req.Email)
-
-	response, err := h.authService.Register(&req)
+	response, err := h.authService.Register(r.Context(), &req, clientInfo(r))
 	if err != nil {
-		log.Printf("Ошибка при регистрации пользователя %s: %v", req.Email, err)
-		errMsg := fmt.Sprintf("Ошибка при регистрации: %v", err)
-		http.Error(w, errMsg, http.StatusBadRequest)
+		apperror.Write(w, r, err)
 		return
 	}
 
-	log.Printf("Пользователь успешно зарегистрирован: %s", req.Email)
+	logging.FromContext(r.Context()).Info("Пользователь зарегистрирован", "user_id", response.User.ID)
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("Ошибка при отправке ответа: %v", err)
-		http.Error(w, "Ошибка сервера", http.StatusInternalServerError)
-		return
+	if err := h.verificationService.SendVerification(r.Context(), response.User); err != nil {
+		logging.FromContext(r.Context()).Error("Ошибка при отправке письма подтверждения", "error", err)
 	}
+
+	middleware.SendJSON(w, http.StatusCreated, response)
 }
 
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req models.LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "неверный формат данных", http.StatusBadRequest)
+		apperror.Write(w, r, apperror.ErrInvalidJSON)
 		return
 	}
 
-	response, err := h.authService.Login(&req)
+	response, challenge, err := h.authService.Login(r.Context(), &req, clientInfo(r))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		apperror.Write(w, r, err)
+		return
+	}
+
+	if challenge != nil {
+		middleware.SendJSON(w, http.StatusOK, challenge)
 		return
 	}
 
 	middleware.SendJSON(w, http.StatusOK, response)
 }
 
+func (h *AuthHandler) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req models.TwoFactorVerifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.Write(w, r, apperror.ErrInvalidJSON)
+		return
+	}
+
+	response, err := h.authService.VerifyTwoFactor(r.Context(), &req, clientInfo(r))
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusOK, response)
+}
+
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.Write(w, r, apperror.ErrInvalidJSON)
+		return
+	}
+
+	response, err := h.authService.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusOK, response)
+}
+
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(int64)
+	sessionID, _ := r.Context().Value("sessionID").(int64)
+
+	if err := h.authService.Logout(r.Context(), userID, sessionID); err != nil && !errors.Is(err, services.ErrSessionNotFound) {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LogoutByRefreshToken завершает сеанс по refresh-токену из тела запроса:
+// токен доступа к моменту выхода мог истечь, и тогда next ответил бы 401,
+// оставив сеанс действующим. Запрос без refresh-токена передаётся next.
+func (h *AuthHandler) LogoutByRefreshToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.RefreshRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			apperror.Write(w, r, apperror.ErrInvalidJSON)
+			return
+		}
+		if req.RefreshToken == "" {
+			next(w, r)
+			return
+		}
+
+		if err := h.authService.LogoutByRefreshToken(r.Context(), req.RefreshToken); err != nil && !errors.Is(err, services.ErrSessionNotFound) {
+			apperror.Write(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(int64)
+
+	if err := h.authService.LogoutAll(r.Context(), userID); err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *AuthHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	user := r.Context().Value("user").(*models.User)
 	if user == nil {
-		http.Error(w, "пользователь не найден", http.StatusNotFound)
+		apperror.Write(w, r, services.ErrUserNotFound)
 		return
 	}
 
@@ -111,27 +201,64 @@ func (h *AuthHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	middleware.SendJSON(w, http.StatusOK, response)
 }
 
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req models.VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.Write(w, r, apperror.ErrInvalidJSON)
+		return
+	}
+
+	if err := h.verificationService.Verify(r.Context(), req.Token); err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(int64)
+
+	if err := h.verificationService.Resend(r.Context(), userID); err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func clientInfo(r *http.Request) models.ClientInfo {
+	userAgent := r.UserAgent()
+	if len(userAgent) > 512 {
+		userAgent = userAgent[:512]
+	}
+	return models.ClientInfo{
+		UserAgent: userAgent,
+		IP:        middleware.ClientIP(r),
+	}
+}
+
 func (h *AuthHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 	user := r.Context().Value("user").(*models.User)
 	if user == nil {
-		http.Error(w, "пользователь не найден", http.StatusNotFound)
+		apperror.Write(w, r, services.ErrUserNotFound)
 		return
 	}
 
 	var req models.UpdateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "неверный формат данных", http.StatusBadRequest)
+		apperror.Write(w, r, apperror.ErrInvalidJSON)
 		return
 	}
 
-	if err := h.authService.UpdateUser(user.ID, &req); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := h.authService.UpdateUser(r.Context(), user.ID, &req); err != nil {
+		apperror.Write(w, r, err)
 		return
 	}
 
-	updatedUser, err := h.authService.GetUserByID(user.ID)
+	updatedUser, err := h.authService.GetUserByID(r.Context(), user.ID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperror.Write(w, r, err)
 		return
 	}
 
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"delivery-service/apperror"
+	"delivery-service/middleware"
+	"delivery-service/models"
+	"delivery-service/services"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type CartHandler struct {
+	cartService  *services.CartService
+	orderService *services.OrderService
+}
+
+func NewCartHandler(cartService *services.CartService, orderService *services.OrderService) *CartHandler {
+	if cartService == nil || orderService == nil {
+		panic("cart and order services are required")
+	}
+	return &CartHandler{cartService: cartService, orderService: orderService}
+}
+
+func (h *CartHandler) GetCart(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		apperror.Write(w, r, apperror.ErrUnauthorized)
+		return
+	}
+
+	cart, err := h.cartService.GetCart(r.Context(), userID)
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusOK, cart)
+}
+
+func (h *CartHandler) ReplaceCart(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		apperror.Write(w, r, apperror.ErrUnauthorized)
+		return
+	}
+
+	var req models.CartItemsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.Write(w, r, apperror.ErrInvalidJSON)
+		return
+	}
+
+	cart, err := h.cartService.ReplaceCart(r.Context(), userID, &req)
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusOK, cart)
+}
+
+func (h *CartHandler) MergeCart(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		apperror.Write(w, r, apperror.ErrUnauthorized)
+		return
+	}
+
+	var req models.CartItemsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.Write(w, r, apperror.ErrInvalidJSON)
+		return
+	}
+
+	cart, err := h.cartService.MergeCart(r.Context(), userID, &req)
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusOK, cart)
+}
+
+func (h *CartHandler) AddItem(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		apperror.Write(w, r, apperror.ErrUnauthorized)
+		return
+	}
+
+	var req models.OrderItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.Write(w, r, apperror.ErrInvalidJSON)
+		return
+	}
+
+	item, err := h.cartService.AddItem(r.Context(), userID, &req)
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusCreated, item)
+}
+
+func (h *CartHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		apperror.Write(w, r, apperror.ErrUnauthorized)
+		return
+	}
+
+	itemID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || itemID <= 0 {
+		apperror.Write(w, r, apperror.InvalidField("id", "invalid_id", "Некорректный ID товара"))
+		return
+	}
+
+	var req models.UpdateCartItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.Write(w, r, apperror.ErrInvalidJSON)
+		return
+	}
+
+	item, err := h.cartService.UpdateItem(r.Context(), userID, itemID, &req)
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusOK, item)
+}
+
+func (h *CartHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		apperror.Write(w, r, apperror.ErrUnauthorized)
+		return
+	}
+
+	itemID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || itemID <= 0 {
+		apperror.Write(w, r, apperror.InvalidField("id", "invalid_id", "Некорректный ID товара"))
+		return
+	}
+
+	if err := h.cartService.RemoveItem(r.Context(), userID, itemID); err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CartHandler) Checkout(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		apperror.Write(w, r, apperror.ErrUnauthorized)
+		return
+	}
+
+	var req models.CheckoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.Write(w, r, apperror.ErrInvalidJSON)
+		return
+	}
+
+	order, err := h.orderService.CheckoutCart(r.Context(), userID, &req)
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusCreated, order)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"delivery-service/buildinfo"
+	"delivery-service/logging"
+	"delivery-service/middleware"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// проверка зависимостей не должна задерживать пробу оркестратора
+const healthCheckTimeout = 2 * time.Second
+
+// HealthChecker - зависимость, без которой экземпляр не готов принимать трафик
+type HealthChecker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// HealthHandler сообщает оркестратору и балансировщику о состоянии экземпляра:
+// /healthz - процесс жив, /readyz - можно направлять запросы, /version - что запущено
+type HealthHandler struct {
+	shuttingDown atomic.Bool
+
+	mu       sync.RWMutex
+	checkers []HealthChecker
+}
+
+type readinessResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+func NewHealthHandler(checkers ...HealthChecker) *HealthHandler {
+	h := &HealthHandler{}
+	for _, checker := range checkers {
+		h.Register(checker)
+	}
+	return h
+}
+
+// Register добавляет проверку готовности
+func (h *HealthHandler) Register(checker HealthChecker) {
+	if checker == nil {
+		panic("health checker is required")
+	}
+	h.mu.Lock()
+	h.checkers = append(h.checkers, checker)
+	h.mu.Unlock()
+}
+
+// MarkShuttingDown переводит /readyz в 503 в начале остановки сервиса,
+// пока начатые запросы ещё дорабатываются
+func (h *HealthHandler) MarkShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// Live отвечает, пока процесс способен обрабатывать запросы. Зависимости
+// не проверяются, чтобы сбой базы не приводил к перезапуску контейнера.
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	middleware.SendJSON(w, http.StatusOK, readinessResponse{Status: "ok"})
+}
+
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	if h.shuttingDown.Load() {
+		middleware.SendJSON(w, http.StatusServiceUnavailable, readinessResponse{Status: "shutting_down"})
+		return
+	}
+
+	h.mu.RLock()
+	checkers := h.checkers
+	h.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+	defer cancel()
+
+	results := make([]error, len(checkers))
+	var wg sync.WaitGroup
+	for i, checker := range checkers {
+		wg.Add(1)
+		go func(i int, checker HealthChecker) {
+			defer wg.Done()
+			results[i] = checker.Check(ctx)
+		}(i, checker)
+	}
+	wg.Wait()
+
+	response := readinessResponse{Status: "ok", Checks: make(map[string]string, len(checkers))}
+	status := http.StatusOK
+	for i, checker := range checkers {
+		if err := results[i]; err != nil {
+			// подробности только в лог: эндпоинт доступен без авторизации
+			logging.FromContext(r.Context()).Warn("Проверка готовности не пройдена", "check", checker.Name(), "error", err)
+			response.Checks[checker.Name()] = "fail"
+			response.Status = "fail"
+			status = http.StatusServiceUnavailable
+			continue
+		}
+		response.Checks[checker.Name()] = "ok"
+	}
+
+	middleware.SendJSON(w, status, response)
+}
+
+func (h *HealthHandler) Version(w http.ResponseWriter, r *http.Request) {
+	middleware.SendJSON(w, http.StatusOK, buildinfo.Get())
+}
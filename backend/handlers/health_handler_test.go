@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type stubChecker struct {
+	name string
+	err  error
+}
+
+func (c stubChecker) Name() string                { return c.name }
+func (c stubChecker) Check(context.Context) error { return c.err }
+
+func TestHealthHandlerReady(t *testing.T) {
+	tests := []struct {
+		name         string
+		checkers     []HealthChecker
+		shuttingDown bool
+		wantStatus   int
+		want         readinessResponse
+	}{
+		{
+			name:       "все проверки пройдены",
+			checkers:   []HealthChecker{stubChecker{name: "database"}},
+			wantStatus: http.StatusOK,
+			want:       readinessResponse{Status: "ok", Checks: map[string]string{"database": "ok"}},
+		},
+		{
+			name:       "зависимость недоступна",
+			checkers:   []HealthChecker{stubChecker{name: "database", err: errors.New("connection refused")}, stubChecker{name: "mailer"}},
+			wantStatus: http.StatusServiceUnavailable,
+			want:       readinessResponse{Status: "fail", Checks: map[string]string{"database": "fail", "mailer": "ok"}},
+		},
+		{
+			// во время остановки зависимости уже не проверяются
+			name:         "остановка сервиса",
+			checkers:     []HealthChecker{stubChecker{name: "database"}},
+			shuttingDown: true,
+			wantStatus:   http.StatusServiceUnavailable,
+			want:         readinessResponse{Status: "shutting_down"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthHandler(tt.checkers...)
+			if tt.shuttingDown {
+				h.MarkShuttingDown()
+			}
+
+			rec := httptest.NewRecorder()
+			h.Ready(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			var got readinessResponse
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("response = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// liveness не зависит от остановки: процесс ещё дорабатывает запросы
+func TestHealthHandlerLiveDuringShutdown(t *testing.T) {
+	h := NewHealthHandler()
+	h.MarkShuttingDown()
+
+	rec := httptest.NewRecorder()
+	h.Live(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rec.Code)
+	}
+}
+
+func TestHealthHandlerVersion(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewHealthHandler().Version(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	var got map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"version", "commit", "build_time", "go_version"} {
+		if got[key] == "" {
+			t.Errorf("%s is empty in %v", key, got)
+		}
+	}
+}
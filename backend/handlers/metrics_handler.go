@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"delivery-service/apperror"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// NewMetricsHandler отдаёт метрики в формате Prometheus. Если token не пуст,
+// scraper должен передать его в заголовке Authorization: Bearer.
+func NewMetricsHandler(token string) http.Handler {
+	handler := promhttp.Handler()
+	if token == "" {
+		return handler
+	}
+
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			apperror.Write(w, r, apperror.ErrUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"delivery-service/apperror"
+	"delivery-service/middleware"
+	"delivery-service/models"
+	"delivery-service/services"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type OrderHandler struct {
+	orderService *services.OrderService
+}
+
+func NewOrderHandler(orderService *services.OrderService) *OrderHandler {
+	if orderService == nil {
+		panic("order service is required")
+	}
+	return &OrderHandler{orderService: orderService}
+}
+
+func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		apperror.Write(w, r, apperror.ErrUnauthorized)
+		return
+	}
+
+	var req models.CreateOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.Write(w, r, apperror.ErrInvalidJSON)
+		return
+	}
+
+	order, err := h.orderService.CreateOrder(r.Context(), userID, &req)
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusCreated, order)
+}
+
+func (h *OrderHandler) ListOrders(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		apperror.Write(w, r, apperror.ErrUnauthorized)
+		return
+	}
+
+	orders, err := h.orderService.ListOrders(r.Context(), userID)
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusOK, orders)
+}
+
+func (h *OrderHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		apperror.Write(w, r, apperror.ErrUnauthorized)
+		return
+	}
+
+	orderID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || orderID <= 0 {
+		apperror.Write(w, r, apperror.InvalidField("id", "invalid_id", "Некорректный номер заказа"))
+		return
+	}
+
+	order, err := h.orderService.GetOrder(r.Context(), userID, orderID)
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusOK, order)
+}
+
+func (h *OrderHandler) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		apperror.Write(w, r, apperror.ErrUnauthorized)
+		return
+	}
+
+	orderID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || orderID <= 0 {
+		apperror.Write(w, r, apperror.InvalidField("id", "invalid_id", "Некорректный номер заказа"))
+		return
+	}
+
+	var req models.CancelOrderRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			apperror.Write(w, r, apperror.ErrInvalidJSON)
+			return
+		}
+	}
+
+	event, err := h.orderService.CancelOrder(r.Context(), userID, orderID, &req)
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusOK, event)
+}
+
+// ChangeStatus - смена статуса заказа сотрудником (оператором или курьером)
+func (h *OrderHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
+	user, ok := r.Context().Value("user").(*models.User)
+	if !ok || user == nil {
+		apperror.Write(w, r, apperror.ErrUnauthorized)
+		return
+	}
+
+	orderID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || orderID <= 0 {
+		apperror.Write(w, r, apperror.InvalidField("id", "invalid_id", "Некорректный номер заказа"))
+		return
+	}
+
+	var req models.ChangeOrderStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.Write(w, r, apperror.ErrInvalidJSON)
+		return
+	}
+
+	if !services.CanSetOrderStatus(user.Role, req.Status) {
+		apperror.Write(w, r, apperror.New(http.StatusForbidden, "status_not_allowed", "недостаточно прав для установки этого статуса"))
+		return
+	}
+
+	event, err := h.orderService.ChangeStatus(r.Context(), orderID, &user.ID, &req)
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusOK, event)
+}
+
+// TrackOrder - публичный поиск заказа по трек-номеру, без авторизации
+func (h *OrderHandler) TrackOrder(w http.ResponseWriter, r *http.Request) {
+	info, err := h.orderService.TrackOrder(r.Context(), mux.Vars(r)["code"])
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	middleware.SendJSON(w, http.StatusOK, info)
+}
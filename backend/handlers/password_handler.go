@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"delivery-service/apperror"
+	"delivery-service/models"
+	"delivery-service/services"
+	"encoding/json"
+	"net/http"
+)
+
+type PasswordHandler struct {
+	passwordService *services.PasswordService
+}
+
+func NewPasswordHandler(passwordService *services.PasswordService) *PasswordHandler {
+	if passwordService == nil {
+		panic("password service is required")
+	}
+	return &PasswordHandler{passwordService: passwordService}
+}
+
+func (h *PasswordHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.Write(w, r, apperror.ErrInvalidJSON)
+		return
+	}
+
+	if err := h.passwordService.ForgotPassword(r.Context(), req.Email); err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	// ответ одинаковый независимо от того, существует ли аккаунт
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *PasswordHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.Write(w, r, apperror.ErrInvalidJSON)
+		return
+	}
+
+	if err := h.passwordService.ResetPassword(r.Context(), req.Token, req.Password, req.ConfirmPassword, clientInfo(r)); err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PasswordHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		apperror.Write(w, r, apperror.ErrUnauthorized)
+		return
+	}
+	sessionID, _ := r.Context().Value("sessionID").(int64)
+
+	var req models.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.Write(w, r, apperror.ErrInvalidJSON)
+		return
+	}
+
+	if err := h.passwordService.ChangePassword(r.Context(), userID, sessionID, &req, clientInfo(r)); err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"delivery-service/apperror"
+	"delivery-service/middleware"
+	"delivery-service/models"
+	"delivery-service/services"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type PickupPointHandler struct {
+	pointService *services.PickupPointService
+}
+
+func NewPickupPointHandler(pointService *services.PickupPointService) *PickupPointHandler {
+	if pointService == nil {
+		panic("pickup point service is required")
+	}
+	return &PickupPointHandler{pointService: pointService}
+}
+
+// Search - GET /api/pickup-points?lat=&lon=&radius= (радиус в метрах)
+func (h *PickupPointHandler) Search(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query := models.PickupPointQuery{}
+
+	var err error
+	if query.Latitude, err = parseOptionalFloat(params.Get("lat")); err != nil {
+		apperror.Write(w, r, apperror.InvalidField("lat", "invalid_number", "Некорректная широта"))
+		return
+	}
+	if query.Longitude, err = parseOptionalFloat(params.Get("lon")); err != nil {
+		apperror.Write(w, r, apperror.InvalidField("lon", "invalid_number", "Некорректная долгота"))
+		return
+	}
+	if radius, err := parseOptionalFloat(params.Get("radius")); err != nil {
+		apperror.Write(w, r, apperror.InvalidField("radius", "invalid_number", "Некорректный радиус"))
+		return
+	} else if radius != nil {
+		query.RadiusMeters = *radius
+	}
+	if limit := params.Get("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil {
+			apperror.Write(w, r, apperror.InvalidField("limit", "invalid_number", "Некорректный limit"))
+			return
+		}
+	}
+
+	points, err := h.pointService.Search(r.Context(), &query)
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusOK, points)
+}
+
+func (h *PickupPointHandler) GetPickupPoint(w http.ResponseWriter, r *http.Request) {
+	h.getPickupPoint(w, r, false)
+}
+
+func (h *PickupPointHandler) AdminGetPickupPoint(w http.ResponseWriter, r *http.Request) {
+	h.getPickupPoint(w, r, true)
+}
+
+func (h *PickupPointHandler) AdminListPickupPoints(w http.ResponseWriter, r *http.Request) {
+	points, err := h.pointService.ListAll(r.Context())
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusOK, points)
+}
+
+func (h *PickupPointHandler) CreatePickupPoint(w http.ResponseWriter, r *http.Request) {
+	var req models.PickupPointRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.Write(w, r, apperror.ErrInvalidJSON)
+		return
+	}
+
+	point, err := h.pointService.CreatePickupPoint(r.Context(), &req)
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusCreated, point)
+}
+
+func (h *PickupPointHandler) UpdatePickupPoint(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id <= 0 {
+		apperror.Write(w, r, apperror.InvalidField("id", "invalid_id", "Некорректный ID пункта выдачи"))
+		return
+	}
+
+	var req models.PickupPointRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperror.Write(w, r, apperror.ErrInvalidJSON)
+		return
+	}
+
+	point, err := h.pointService.UpdatePickupPoint(r.Context(), id, &req)
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusOK, point)
+}
+
+func (h *PickupPointHandler) DeletePickupPoint(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id <= 0 {
+		apperror.Write(w, r, apperror.InvalidField("id", "invalid_id", "Некорректный ID пункта выдачи"))
+		return
+	}
+
+	if err := h.pointService.DeletePickupPoint(r.Context(), id); err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PickupPointHandler) getPickupPoint(w http.ResponseWriter, r *http.Request, includeInactive bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id <= 0 {
+		apperror.Write(w, r, apperror.InvalidField("id", "invalid_id", "Некорректный ID пункта выдачи"))
+		return
+	}
+
+	point, err := h.pointService.GetPickupPoint(r.Context(), id, includeInactive)
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusOK, point)
+}
+
+func parseOptionalFloat(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"delivery-service/apperror"
+	"delivery-service/middleware"
+	"delivery-service/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type SessionHandler struct {
+	sessionService *services.SessionService
+}
+
+func NewSessionHandler(sessionService *services.SessionService) *SessionHandler {
+	if sessionService == nil {
+		panic("session service is required")
+	}
+	return &SessionHandler{sessionService: sessionService}
+}
+
+func (h *SessionHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		apperror.Write(w, r, apperror.ErrUnauthorized)
+		return
+	}
+	currentID, _ := r.Context().Value("sessionID").(int64)
+
+	sessions, err := h.sessionService.ListSessions(r.Context(), userID, currentID)
+	if err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	middleware.SendJSON(w, http.StatusOK, sessions)
+}
+
+func (h *SessionHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		apperror.Write(w, r, apperror.ErrUnauthorized)
+		return
+	}
+
+	sessionID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || sessionID <= 0 {
+		apperror.Write(w, r, apperror.InvalidField("id", "invalid_id", "Некорректный ID сеанса"))
+		return
+	}
+
+	if err := h.sessionService.RevokeSession(r.Context(), userID, sessionID); err != nil {
+		apperror.Write(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
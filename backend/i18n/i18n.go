@@ -0,0 +1,138 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Lang - код языка ISO 639-1
+type Lang string
+
+const (
+	RU Lang = "ru"
+	EN Lang = "en"
+
+	// Default используется, если язык клиента неизвестен или не поддерживается
+	Default = RU
+)
+
+var catalogs = map[Lang]map[string]string{
+	RU: ru,
+	EN: en,
+}
+
+type contextKey struct{}
+
+// Parse приводит тег языка (ru, en-US, EN_gb) к поддерживаемому языку
+func Parse(tag string) (Lang, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	lang := Lang(tag)
+	if _, ok := catalogs[lang]; !ok {
+		return "", false
+	}
+	return lang, true
+}
+
+// Preferred возвращает язык из настроек пользователя или fallback,
+// если язык не задан или не поддерживается
+func Preferred(preferred *string, fallback Lang) Lang {
+	if preferred != nil {
+		if lang, ok := Parse(*preferred); ok {
+			return lang
+		}
+	}
+	return fallback
+}
+
+// FromAcceptLanguage выбирает поддерживаемый язык с наибольшим весом q
+// из заголовка Accept-Language
+func FromAcceptLanguage(header string) Lang {
+	type candidate struct {
+		lang Lang
+		q    float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		lang, ok := Parse(tag)
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{lang, q})
+		}
+	}
+	if len(candidates) == 0 {
+		return Default
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
+
+func WithLanguage(ctx context.Context, lang Lang) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// FromContext возвращает язык запроса или Default
+func FromContext(ctx context.Context) Lang {
+	if lang, ok := ctx.Value(contextKey{}).(Lang); ok {
+		return lang
+	}
+	return Default
+}
+
+// FromRequest возвращает язык из контекста запроса, а для запросов,
+// не прошедших через middleware (например, 404 роутера), - из Accept-Language
+func FromRequest(r *http.Request) Lang {
+	if lang, ok := r.Context().Value(contextKey{}).(Lang); ok {
+		return lang
+	}
+	return FromAcceptLanguage(r.Header.Get("Accept-Language"))
+}
+
+// Lookup ищет сообщение в каталоге языка, затем в каталоге Default.
+// args - пары имя/значение для подстановки {имя} в текст.
+func Lookup(lang Lang, key string, args ...string) (string, bool) {
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		msg, ok = catalogs[Default][key]
+	}
+	if !ok {
+		return "", false
+	}
+	return format(msg, args), true
+}
+
+// T возвращает сообщение по ключу. Неизвестный ключ возвращается как есть,
+// чтобы пропущенный перевод был заметен, но не ломал ответ.
+func T(lang Lang, key string, args ...string) string {
+	if msg, ok := Lookup(lang, key, args...); ok {
+		return msg
+	}
+	return key
+}
+
+func format(msg string, args []string) string {
+	if len(args) == 0 {
+		return msg
+	}
+	pairs := make([]string, 0, len(args))
+	for i := 0; i+1 < len(args); i += 2 {
+		pairs = append(pairs, "{"+args[i]+"}", args[i+1])
+	}
+	return strings.NewReplacer(pairs...).Replace(msg)
+}
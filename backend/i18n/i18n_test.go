@@ -0,0 +1,130 @@
+package i18n
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFromAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   Lang
+	}{
+		{"пустой заголовок", "", Default},
+		{"один язык", "en", EN},
+		{"регион", "en-US", EN},
+		{"порядок при равном весе", "en, ru", EN},
+		{"вес q", "en;q=0.5, ru;q=0.8", RU},
+		{"вес без q у первого", "ru-RU,ru;q=0.9,en-US;q=0.8,en;q=0.7", RU},
+		{"неподдерживаемые пропускаются", "de-DE, fr;q=0.9, en;q=0.1", EN},
+		{"только неподдерживаемые", "de, fr", Default},
+		{"q=0 запрещает язык", "en;q=0, de", Default},
+		{"некорректный вес", "en;q=abc, ru;q=0.1", RU},
+		{"пробелы и регистр", "  EN_gb ; q=0.9 ", EN},
+		{"звёздочка", "*", Default},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromAcceptLanguage(tt.header); got != tt.want {
+				t.Errorf("FromAcceptLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		tag    string
+		want   Lang
+		wantOK bool
+	}{
+		{"ru", RU, true},
+		{"en-US", EN, true},
+		{"EN_gb", EN, true},
+		{" ru ", RU, true},
+		{"de", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Parse(tt.tag)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("Parse(%q) = %q, %v, want %q, %v", tt.tag, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestPreferred(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name      string
+		preferred *string
+		want      Lang
+	}{
+		{"не задан", nil, EN},
+		{"поддерживается", str("ru"), RU},
+		{"не поддерживается", str("de"), EN},
+		{"пустой", str(""), EN},
+	}
+	for _, tt := range tests {
+		if got := Preferred(tt.preferred, EN); got != tt.want {
+			t.Errorf("%s: Preferred() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFromRequest(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Language", "en")
+	if got := FromRequest(req); got != EN {
+		t.Errorf("FromRequest() by header = %q, want en", got)
+	}
+	// язык из контекста важнее заголовка
+	req = req.WithContext(WithLanguage(req.Context(), RU))
+	if got := FromRequest(req); got != RU {
+		t.Errorf("FromRequest() by context = %q, want ru", got)
+	}
+	if got := FromContext(context.Background()); got != Default {
+		t.Errorf("FromContext() = %q, want %q", got, Default)
+	}
+}
+
+func TestT(t *testing.T) {
+	tests := []struct {
+		name string
+		lang Lang
+		key  string
+		args []string
+		want string
+	}{
+		{"перевод", EN, "not_found", nil, "Resource not found"},
+		{"подстановка", EN, "invalid_number", []string{"field", "limit"}, "Invalid value for parameter limit"},
+		{"лишний аргумент без пары", EN, "invalid_number", []string{"field", "limit", "extra"}, "Invalid value for parameter limit"},
+		{"неизвестный язык - Default", Lang("de"), "invalid_number", []string{"field", "limit"}, "Некорректное значение параметра limit"},
+		{"неизвестный ключ", EN, "no_such_key", nil, "no_such_key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := T(tt.lang, tt.key, tt.args...); got != tt.want {
+				t.Errorf("T() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Каждое сообщение должно быть переведено на все языки
+func TestCatalogsComplete(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for key := range catalogs[Default] {
+			if _, ok := catalog[key]; !ok {
+				t.Errorf("%s: missing %q", lang, key)
+			}
+		}
+		for key := range catalog {
+			if _, ok := catalogs[Default][key]; !ok {
+				t.Errorf("%s: %q is not in the %s catalog", lang, key, Default)
+			}
+		}
+	}
+}
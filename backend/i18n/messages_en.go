@@ -0,0 +1,103 @@
+package i18n
+
+var en = map[string]string{
+	// общие ошибки API
+	"bad_request":            "Bad request",
+	"invalid_json":           "Malformed request body",
+	"validation_failed":      "Please check the data you entered",
+	"unauthorized":           "Authentication required",
+	"forbidden":              "Insufficient permissions",
+	"not_found":              "Resource not found",
+	"method_not_allowed":     "Method not allowed",
+	"unsupported_media_type": "Content-Type: application/json is required",
+	"rate_limited":           "Too many requests, please try again later",
+	"internal_error":         "Internal server error",
+
+	// параметры запроса
+	"required":        "This field is required",
+	"invalid_id":      "Invalid identifier",
+	"invalid_number":  "Invalid value for parameter {field}",
+	"invalid_boolean": "Invalid value for parameter {field}",
+	"invalid_date":    "Invalid date in {field} (expected YYYY-MM-DD)",
+
+	// авторизация
+	"missing_token":              "Authorization token is missing",
+	"malformed_token":            "Malformed authorization token",
+	"invalid_token":              "Invalid token",
+	"invalid_refresh_token":      "Invalid refresh token",
+	"invalid_credentials":        "Invalid email or password",
+	"invalid_challenge":          "The sign-in confirmation has expired, please sign in again",
+	"too_many_login_attempts":    "Too many failed sign-in attempts, please try again later",
+	"user_blocked":               "Your account has been blocked, please contact support",
+	"session_not_found":          "Session not found",
+	"email_not_verified":         "Please verify your email to place orders",
+	"invalid_verification_token": "The email verification link is invalid or has expired",
+	"email_already_verified":     "Email is already verified",
+	"invalid_reset_token":        "The password reset link is invalid or has expired",
+	"two_factor_already_enabled": "Two-factor authentication is already enabled",
+	"two_factor_not_enabled":     "Two-factor authentication is not enabled",
+	"two_factor_setup_required":  "Start two-factor authentication setup first",
+	"invalid_two_factor_code":    "Invalid confirmation code",
+
+	// пользователи
+	"user_exists":               "A user with this email already exists",
+	"user_not_found":            "User not found",
+	"invalid_input":             "Invalid input",
+	"invalid_email":             "Invalid email",
+	"invalid_password":          "Password must be at least 8 characters long",
+	"invalid_name":              "Name must be 2 to 100 characters: letters, spaces, hyphens or apostrophes",
+	"invalid_birth_date":        "Invalid birth date (expected YYYY-MM-DD)",
+	"birth_date_in_future":      "Birth date cannot be in the future",
+	"invalid_phone":             "Enter the phone number in international format, e.g. +12025550123",
+	"invalid_whatsapp":          "Enter the WhatsApp number in international format, e.g. +12025550123",
+	"invalid_telegram":          "Telegram username: 5-32 Latin letters, digits or underscores",
+	"invalid_postal_code":       "Invalid postal code for the specified country",
+	"invalid_language":          "Enter a two-letter ISO 639-1 language code, e.g. en",
+	"invalid_preferred_contact": "Preferred contact must be one of: phone, telegram, whatsapp, email",
+	"password_mismatch":         "Passwords do not match",
+	"wrong_current_password":    "Current password is incorrect",
+	"same_password":             "The new password must differ from the current one",
+	"invalid_role":              "Unknown role",
+	"cannot_modify_self":        "You cannot block yourself or change your own role",
+	"invalid_filter":            "Invalid search parameters",
+
+	// корзина и заказы
+	"cart_item_not_found":       "Cart item not found",
+	"cart_full":                 "The cart cannot contain more than 50 items",
+	"cart_empty":                "The cart is empty",
+	"order_not_found":           "Order not found",
+	"empty_order":               "An order must contain at least one item",
+	"invalid_order_item":        "Each item needs a marketplace and a valid link",
+	"invalid_quantity":          "Quantity must be greater than zero",
+	"invalid_delivery":          "Delivery address, date and time are required",
+	"invalid_delivery_date":     "Invalid delivery date or time format",
+	"too_many_order_items":      "An order cannot contain more than 50 items",
+	"invalid_order_status":      "Unknown order status",
+	"invalid_status_transition": "This order status change is not allowed",
+	"status_conflict":           "The order status has changed, refresh and try again",
+	"status_not_allowed":        "You are not allowed to set this status",
+	"order_not_cancellable":     "The order can no longer be cancelled",
+	"invalid_tracking_code":     "Invalid tracking number",
+
+	// пункты выдачи
+	"pickup_point_not_found": "Pickup point not found",
+	"invalid_pickup_point":   "Pickup point name, address and city are required",
+	"invalid_coordinates":    "Invalid coordinates",
+	"invalid_timezone":       "Unknown time zone",
+	"invalid_working_hours":  "Invalid working hours (expected HH:MM for days mon..sun)",
+	"invalid_capacity":       "Capacity cannot be negative",
+	"invalid_search_radius":  "Search radius must be between 1 m and 100 km",
+	"incomplete_coordinates": "Both latitude and longitude are required for search",
+
+	// письма
+	"email.greeting":       "Hello, {name}!",
+	"email.verify.subject": "Email verification",
+	"email.verify.body": "Please confirm your email address by following the link:\n{link}\n\n" +
+		"The link is valid for 48 hours.\n",
+	"email.reset.subject": "Password recovery",
+	"email.reset.body": "To set a new password, follow the link:\n{link}\n\n" +
+		"The link is valid for 1 hour. If you did not request a password reset, simply ignore this email.\n",
+	"email.reset_forced.body": "An administrator has reset the password for your account. " +
+		"To set a new password, follow the link:\n{link}\n\n" +
+		"The link is valid for 1 hour. If it has expired, use password recovery on the sign-in page.\n",
+}
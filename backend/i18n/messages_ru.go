@@ -0,0 +1,105 @@
+package i18n
+
+// Ключи ошибок совпадают с кодами apperror, чтобы код и текст ответа
+// находились по одному и тому же значению
+var ru = map[string]string{
+	// общие ошибки API
+	"bad_request":            "Некорректный запрос",
+	"invalid_json":           "Неверный формат данных",
+	"validation_failed":      "Проверьте введённые данные",
+	"unauthorized":           "Требуется авторизация",
+	"forbidden":              "Недостаточно прав",
+	"not_found":              "Ресурс не найден",
+	"method_not_allowed":     "Метод не разрешён",
+	"unsupported_media_type": "Требуется Content-Type: application/json",
+	"rate_limited":           "Слишком много запросов, попробуйте позже",
+	"internal_error":         "Ошибка сервера",
+
+	// параметры запроса
+	"required":        "Поле обязательно для заполнения",
+	"invalid_id":      "Некорректный идентификатор",
+	"invalid_number":  "Некорректное значение параметра {field}",
+	"invalid_boolean": "Некорректное значение параметра {field}",
+	"invalid_date":    "Некорректная дата {field} (требуется YYYY-MM-DD)",
+
+	// авторизация
+	"missing_token":              "Отсутствует токен авторизации",
+	"malformed_token":            "Неверный формат токена",
+	"invalid_token":              "Недействительный токен",
+	"invalid_refresh_token":      "Недействительный refresh-токен",
+	"invalid_credentials":        "Неверный email или пароль",
+	"invalid_challenge":          "Время на подтверждение входа истекло, войдите заново",
+	"too_many_login_attempts":    "Слишком много неудачных попыток входа, попробуйте позже",
+	"user_blocked":               "Учётная запись заблокирована, обратитесь в поддержку",
+	"session_not_found":          "Сеанс не найден",
+	"email_not_verified":         "Подтвердите email, чтобы оформлять заказы",
+	"invalid_verification_token": "Ссылка для подтверждения email недействительна или устарела",
+	"email_already_verified":     "Email уже подтверждён",
+	"invalid_reset_token":        "Ссылка для сброса пароля недействительна или устарела",
+	"two_factor_already_enabled": "Двухфакторная аутентификация уже включена",
+	"two_factor_not_enabled":     "Двухфакторная аутентификация не включена",
+	"two_factor_setup_required":  "Сначала начните подключение двухфакторной аутентификации",
+	"invalid_two_factor_code":    "Неверный код подтверждения",
+
+	// пользователи
+	"user_exists":               "Пользователь с таким email уже существует",
+	"user_not_found":            "Пользователь не найден",
+	"invalid_input":             "Некорректные входные данные",
+	"invalid_email":             "Некорректный email",
+	"invalid_password":          "Пароль должен содержать минимум 8 символов",
+	"invalid_name":              "Имя должно содержать от 2 до 100 символов: буквы, пробелы, дефисы или апострофы",
+	"invalid_birth_date":        "Неверный формат даты рождения (требуется YYYY-MM-DD)",
+	"birth_date_in_future":      "Дата рождения не может быть в будущем",
+	"invalid_phone":             "Укажите номер телефона в международном формате, например +79991234567",
+	"invalid_whatsapp":          "Укажите номер WhatsApp в международном формате, например +79991234567",
+	"invalid_telegram":          "Имя пользователя Telegram: 5-32 латинские буквы, цифры или подчёркивание",
+	"invalid_postal_code":       "Некорректный почтовый индекс для указанной страны",
+	"invalid_language":          "Укажите двухбуквенный код языка ISO 639-1, например ru",
+	"invalid_preferred_contact": "Способ связи должен быть одним из: phone, telegram, whatsapp, email",
+	"password_mismatch":         "Пароли не совпадают",
+	"wrong_current_password":    "Текущий пароль указан неверно",
+	"same_password":             "Новый пароль должен отличаться от текущего",
+	"invalid_role":              "Неизвестная роль",
+	"cannot_modify_self":        "Нельзя заблокировать себя или изменить свою роль",
+	"invalid_filter":            "Некорректные параметры поиска",
+
+	// корзина и заказы
+	"cart_item_not_found":       "Товар в корзине не найден",
+	"cart_full":                 "В корзине не может быть больше 50 позиций",
+	"cart_empty":                "Корзина пуста",
+	"order_not_found":           "Заказ не найден",
+	"empty_order":               "Заказ должен содержать хотя бы один товар",
+	"invalid_order_item":        "Для каждого товара нужно указать маркетплейс и корректную ссылку",
+	"invalid_quantity":          "Количество товара должно быть больше нуля",
+	"invalid_delivery":          "Необходимо указать адрес, дату и время доставки",
+	"invalid_delivery_date":     "Неверный формат даты или времени доставки",
+	"too_many_order_items":      "Заказ не может содержать больше 50 товаров",
+	"invalid_order_status":      "Неизвестный статус заказа",
+	"invalid_status_transition": "Недопустимая смена статуса заказа",
+	"status_conflict":           "Статус заказа был изменён, обновите данные и повторите попытку",
+	"status_not_allowed":        "Недостаточно прав для установки этого статуса",
+	"order_not_cancellable":     "Заказ уже нельзя отменить",
+	"invalid_tracking_code":     "Некорректный трек-номер",
+
+	// пункты выдачи
+	"pickup_point_not_found": "Пункт выдачи не найден",
+	"invalid_pickup_point":   "Необходимо указать название, адрес и город пункта выдачи",
+	"invalid_coordinates":    "Некорректные координаты",
+	"invalid_timezone":       "Неизвестный часовой пояс",
+	"invalid_working_hours":  "Некорректное расписание работы (требуется HH:MM для дней mon..sun)",
+	"invalid_capacity":       "Вместимость не может быть отрицательной",
+	"invalid_search_radius":  "Радиус поиска должен быть от 1 м до 100 км",
+	"incomplete_coordinates": "Для поиска нужно указать и широту, и долготу",
+
+	// письма
+	"email.greeting":       "Здравствуйте, {name}!",
+	"email.verify.subject": "Подтверждение email",
+	"email.verify.body": "Подтвердите адрес электронной почты, перейдя по ссылке:\n{link}\n\n" +
+		"Ссылка действует 48 часов.\n",
+	"email.reset.subject": "Восстановление пароля",
+	"email.reset.body": "Чтобы задать новый пароль, перейдите по ссылке:\n{link}\n\n" +
+		"Ссылка действует 1 час. Если вы не запрашивали сброс пароля, просто проигнорируйте это письмо.\n",
+	"email.reset_forced.body": "Администратор сбросил пароль вашей учётной записи. " +
+		"Чтобы задать новый пароль, перейдите по ссылке:\n{link}\n\n" +
+		"Ссылка действует 1 час. Если она истекла, воспользуйтесь восстановлением пароля на странице входа.\n",
+}
@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// Категории данных, которые можно скрывать в логах (LOG_REDACT)
+const (
+	RedactEmail = "email"
+	RedactPhone = "phone"
+	RedactToken = "token"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	phonePattern = regexp.MustCompile(`(?:\+?\d{1,3}[\s-]?)?\(?\d{3}\)?[\s-]?\d{3}[\s-]?\d{2}[\s-]?\d{2}\b`)
+	// JWT, заголовок Authorization и токены в ссылках (?token=...)
+	tokenPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+		regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]+`),
+		regexp.MustCompile(`(?i)((?:token|code)=)[^&\s"]+`),
+	}
+
+	tokenKeys = map[string]bool{
+		"token":           true,
+		"refresh_token":   true,
+		"challenge_token": true,
+		"authorization":   true,
+		"password":        true,
+		"secret":          true,
+		"code":            true,
+	}
+)
+
+// Redactor скрывает персональные данные и секреты в сообщениях и атрибутах.
+// Атрибуты с известными именами (email, phone, token...) маскируются целиком,
+// в остальных строках заменяются найденные по шаблону значения.
+type Redactor struct {
+	email bool
+	phone bool
+	token bool
+}
+
+func NewRedactor(categories []string) *Redactor {
+	r := &Redactor{}
+	for _, category := range categories {
+		switch strings.ToLower(strings.TrimSpace(category)) {
+		case RedactEmail:
+			r.email = true
+		case RedactPhone:
+			r.phone = true
+		case RedactToken:
+			r.token = true
+		}
+	}
+	return r
+}
+
+// ReplaceAttr подходит для slog.HandlerOptions
+func (r *Redactor) ReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() == slog.KindAny {
+		if err, ok := a.Value.Any().(error); ok {
+			a.Value = slog.StringValue(err.Error())
+		}
+	}
+	if a.Value.Kind() != slog.KindString {
+		return a
+	}
+
+	value := a.Value.String()
+	switch key := strings.ToLower(a.Key); {
+	case r.email && key == "email":
+		value = maskEmail(value)
+	case r.phone && key == "phone":
+		value = maskPhone(value)
+	case r.token && tokenKeys[key]:
+		value = redacted
+	default:
+		value = r.Redact(value)
+	}
+	return slog.String(a.Key, value)
+}
+
+// Redact заменяет в строке всё, что похоже на email, телефон или токен
+func (r *Redactor) Redact(s string) string {
+	if r.token {
+		for i, pattern := range tokenPatterns {
+			if i == 0 {
+				s = pattern.ReplaceAllString(s, redacted)
+			} else {
+				s = pattern.ReplaceAllString(s, "${1}"+redacted)
+			}
+		}
+	}
+	if r.email {
+		s = emailPattern.ReplaceAllStringFunc(s, maskEmail)
+	}
+	if r.phone {
+		s = phonePattern.ReplaceAllStringFunc(s, maskPhone)
+	}
+	return s
+}
+
+// maskEmail оставляет первую букву и домен: i***@example.com
+func maskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return redacted
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// maskPhone оставляет две последние цифры
+func maskPhone(phone string) string {
+	digits := make([]byte, 0, len(phone))
+	for i := 0; i < len(phone); i++ {
+		if phone[i] >= '0' && phone[i] <= '9' {
+			digits = append(digits, phone[i])
+		}
+	}
+	if len(digits) < 4 {
+		return redacted
+	}
+	return "***" + string(digits[len(digits)-2:])
+}
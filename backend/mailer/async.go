@@ -0,0 +1,94 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+var (
+	ErrMailerClosed = errors.New("mailer is closed")
+	ErrQueueFull    = errors.New("mail queue is full")
+)
+
+const (
+	asyncWorkers   = 4
+	asyncQueueSize = 256
+)
+
+// AsyncMailer отправляет письма в фоне, чтобы время ответа не зависело
+// от почтового сервера. Письма ждут в очереди ограниченного размера
+// и отправляются несколькими воркерами, поэтому медленный сервер не
+// порождает неограниченное число горутин. При остановке сервиса Close
+// дожидается отправки уже принятых писем.
+type AsyncMailer struct {
+	mailer Mailer
+	queue  chan Message
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func NewAsync(m Mailer) *AsyncMailer {
+	if m == nil {
+		panic("mailer is required")
+	}
+	a := &AsyncMailer{mailer: m, queue: make(chan Message, asyncQueueSize)}
+	a.wg.Add(asyncWorkers)
+	for i := 0; i < asyncWorkers; i++ {
+		go a.work()
+	}
+	return a
+}
+
+// Send ставит письмо в очередь и сразу возвращает управление.
+// Если очередь заполнена, возвращает ErrQueueFull. Ошибки отправки пишутся в лог.
+func (m *AsyncMailer) Send(msg Message) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return ErrMailerClosed
+	}
+
+	select {
+	case m.queue <- msg:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func (m *AsyncMailer) work() {
+	defer m.wg.Done()
+	for msg := range m.queue {
+		if err := m.mailer.Send(msg); err != nil {
+			slog.Error("Ошибка при отправке письма", "subject", msg.Subject, "error", err)
+		}
+	}
+}
+
+// Close перестаёт принимать письма и ждёт, пока воркеры разберут очередь,
+// но не дольше, чем позволяет ctx
+func (m *AsyncMailer) Close(ctx context.Context) error {
+	m.mu.Lock()
+	if !m.closed {
+		m.closed = true
+		close(m.queue)
+	}
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
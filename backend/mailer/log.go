@@ -0,0 +1,57 @@
+package mailer
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogMailer только отмечает письма в логе, используется при локальной
+// разработке. Тело письма не пишется: в нём ссылки со сброса пароля и
+// подтверждения email. Прочитать письма целиком позволяет FileMailer.
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Send(msg Message) error {
+	slog.Info("Письмо", "email", msg.To, "subject", msg.Subject)
+	return nil
+}
+
+// FileMailer сохраняет каждое письмо отдельным файлом в каталоге,
+// чтобы тесты и разработчики могли прочитать отправленные ссылки
+type FileMailer struct {
+	dir string
+
+	mu  sync.Mutex
+	seq int
+}
+
+func NewFileMailer(dir string) (*FileMailer, error) {
+	if dir == "" {
+		dir = "mail"
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("error creating mail directory: %w", err)
+	}
+	return &FileMailer{dir: dir}, nil
+}
+
+func (m *FileMailer) Send(msg Message) error {
+	m.mu.Lock()
+	m.seq++
+	seq := m.seq
+	m.mu.Unlock()
+
+	recipient := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_").Replace(msg.To)
+	name := fmt.Sprintf("%s-%03d-%s.txt", time.Now().Format("20060102-150405"), seq, recipient)
+	content := fmt.Sprintf("To: %s\nSubject: %s\n\n%s\n", msg.To, msg.Subject, msg.Body)
+
+	return os.WriteFile(filepath.Join(m.dir, name), []byte(content), 0o640)
+}
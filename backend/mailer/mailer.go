@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"delivery-service/config"
+	"fmt"
+	"strconv"
+)
+
+// Message - письмо в виде обычного текста
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer отправляет письма пользователям
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// New выбирает реализацию по cfg.Driver: smtp - отправка через SMTP,
+// file - сохранение писем в каталог cfg.Dir, log - вывод писем в лог
+func New(cfg config.MailConfig) (Mailer, error) {
+	switch cfg.Driver {
+	case config.MailerSMTP:
+		return NewSMTPMailer(SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     strconv.Itoa(cfg.SMTPPort),
+			Username: cfg.SMTPUser,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		})
+	case config.MailerFile:
+		return NewFileMailer(cfg.Dir)
+	case "", config.MailerLog:
+		return NewLogMailer(), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,102 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingMailer struct {
+	mu      sync.Mutex
+	sent    []Message
+	release chan struct{}
+}
+
+func (m *recordingMailer) Send(msg Message) error {
+	if m.release != nil {
+		<-m.release
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func TestAsyncMailerDeliversBeforeClose(t *testing.T) {
+	rec := &recordingMailer{}
+	m := NewAsync(rec)
+	for i := 0; i < 10; i++ {
+		if err := m.Send(Message{To: "user@example.com", Subject: "Тест"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Close дожидается отправки уже принятых писем
+	if err := m.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.sent) != 10 {
+		t.Errorf("sent %d messages, want 10", len(rec.sent))
+	}
+	if err := m.Send(Message{}); !errors.Is(err, ErrMailerClosed) {
+		t.Errorf("Send after Close = %v, want ErrMailerClosed", err)
+	}
+}
+
+func TestAsyncMailerQueueFull(t *testing.T) {
+	rec := &recordingMailer{release: make(chan struct{})}
+	m := NewAsync(rec)
+
+	// воркеры заняты, поэтому очередь вмещает не больше asyncWorkers+asyncQueueSize писем
+	var err error
+	for i := 0; i <= asyncWorkers+asyncQueueSize && err == nil; i++ {
+		err = m.Send(Message{})
+	}
+	if !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Send = %v, want ErrQueueFull", err)
+	}
+
+	// почтовый сервер завис: Close возвращается по истечении ctx
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := m.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Close = %v, want DeadlineExceeded", err)
+	}
+	close(rec.release)
+}
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	m, err := NewFileMailer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Send(Message{To: "../user@example.com", Subject: "Сброс пароля", Body: "https://example.com/reset"}); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("got %d files, want 1", len(files))
+	}
+	// адрес не должен выводить файл за пределы каталога
+	if name := files[0].Name(); !strings.HasSuffix(name, "-001-.._user_at_example.com.txt") {
+		t.Errorf("file name = %q", name)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "To: ../user@example.com\nSubject: Сброс пароля\n\nhttps://example.com/reset\n"
+	if string(content) != want {
+		t.Errorf("content = %q, want %q", content, want)
+	}
+}
@@ -1,49 +1,145 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
+	"delivery-service/apperror"
+	"delivery-service/config"
 	"delivery-service/db"
 	"delivery-service/handlers"
+	"delivery-service/logging"
+	"delivery-service/mailer"
+	"delivery-service/metrics"
 	"delivery-service/middleware"
+	"delivery-service/models"
 	"delivery-service/repository"
 	"delivery-service/services"
-	"fmt"
-	"log"
+	"delivery-service/tracing"
+	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/gorilla/mux"
-	"github.com/joho/godotenv"
 )
 
 func main() {
-	// Загрузка переменных окружения
-	if err := godotenv.Load(); err != nil {
-		log.Printf("Warning: .env file not found, using system environment variables")
+	// Загрузка и проверка настроек
+	cfg, err := config.Load()
+	if err != nil {
+		fatal("Ошибка в настройках", err)
 	}
+	logging.Setup(cfg.Log, os.Stdout)
 
 	// Инициализация базы данных
-	if err := db.InitDB(); err != nil {
-		log.Fatal("Error initializing database:", err)
+	if err := db.InitDB(cfg.Database); err != nil {
+		fatal("Ошибка подключения к базе данных", err)
 	}
-	defer db.DB.Close()
+
+	// migrate up|down [n]|status - управление миграциями без запуска сервера
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := runMigrateCommand(os.Args[2:])
+		db.DB.Close()
+		if err != nil {
+			fatal("Ошибка миграции", err)
+		}
+		return
+	}
+
+	shutdownTracing, err := tracing.Setup(cfg.Tracing)
+	if err != nil {
+		fatal("Ошибка настройки трассировки", err)
+	}
+
+	migrator, err := applyMigrations()
+	if err != nil {
+		fatal("Ошибка применения миграций", err)
+	}
+	metrics.RegisterDB(db.DB, "postgres")
 
 	// Инициализация репозиториев, сервисов и обработчиков
 	userRepo := repository.NewUserRepository(db.DB)
-	authService := services.NewAuthService(userRepo)
-	authHandler := handlers.NewAuthHandler(authService)
-	authMiddleware := middleware.NewAuthMiddleware(authService)
+	// ADMIN_EMAILS оставлен для первичной настройки: перечисленным
+	// пользователям при запуске назначается роль администратора
+	if len(cfg.Auth.AdminEmails) > 0 {
+		if _, err := userRepo.SetRoleByEmails(context.Background(), cfg.Auth.AdminEmails, models.RoleAdmin); err != nil {
+			slog.Error("Ошибка при назначении роли администратора", "error", err)
+		}
+	}
+	sessionRepo := repository.NewSessionRepository(db.DB)
+	auditRepo := repository.NewAuditRepository(db.DB)
+	twoFactorRepo := repository.NewTwoFactorRepository(db.DB)
+	twoFactorService := services.NewTwoFactorService(cfg, userRepo, twoFactorRepo, auditRepo)
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorService)
+	twoFactorLimiter := middleware.NewRateLimiter(middleware.RateLimitPolicy{Limit: 10, Period: 5 * time.Minute})
+	var loginAttempts services.LoginAttemptStore = services.NewMemoryLoginAttemptStore()
+	if cfg.Auth.LoginAttemptsStore == config.LoginAttemptsPostgres {
+		loginAttempts = repository.NewLoginAttemptRepository(db.DB)
+	}
+	authService := services.NewAuthService(cfg, userRepo, sessionRepo, twoFactorService, services.NewLoginGuard(loginAttempts))
+	mailSender, err := mailer.New(cfg.Mail)
+	if err != nil {
+		fatal("Ошибка инициализации отправки писем", err)
+	}
+	mailQueue := mailer.NewAsync(mailSender)
+	verificationService := services.NewEmailVerificationService(cfg, userRepo, mailQueue)
+	authHandler := handlers.NewAuthHandler(authService, verificationService)
+	verificationLimiter := middleware.NewRateLimiter(middleware.RateLimitPolicy{Limit: 5, Period: 15 * time.Minute, Key: middleware.KeyByUser})
+	passwordResetRepo := repository.NewPasswordResetRepository(db.DB)
+	passwordService := services.NewPasswordService(cfg, userRepo, passwordResetRepo, sessionRepo, auditRepo, mailQueue)
+	passwordHandler := handlers.NewPasswordHandler(passwordService)
+	adminUserService := services.NewAdminUserService(userRepo, auditRepo)
+	adminUserHandler := handlers.NewAdminUserHandler(adminUserService, passwordService)
+	passwordLimiter := middleware.NewRateLimiter(middleware.RateLimitPolicy{Limit: 5, Period: 15 * time.Minute})
+
+	sessionActivity := services.NewSessionActivity(sessionRepo)
+	sessionActivity.Start(context.Background())
+	sessionService := services.NewSessionService(sessionRepo, sessionActivity)
+	sessionHandler := handlers.NewSessionHandler(sessionService)
+	authMiddleware := middleware.NewAuthMiddleware(cfg, authService, sessionActivity)
+
+	orderRepo := repository.NewOrderRepository(db.DB)
+	orderService := services.NewOrderService(orderRepo)
+	orderHandler := handlers.NewOrderHandler(orderService)
+	// партнёры с ключом из PARTNER_API_KEYS получают отдельный лимит, остальные - по IP
+	trackingLimiter := middleware.NewRateLimiter(middleware.RateLimitPolicy{Limit: 30, Period: time.Minute, Key: middleware.KeyByAPIKey(cfg.Server.PartnerAPIKeys)})
+	orderLimiter := middleware.NewRateLimiter(middleware.RateLimitPolicy{Limit: 20, Period: time.Hour, Key: middleware.KeyByUser})
+
+	cartRepo := repository.NewCartRepository(db.DB)
+	cartService := services.NewCartService(cartRepo)
+	cartHandler := handlers.NewCartHandler(cartService, orderService)
+
+	pickupPointRepo := repository.NewPickupPointRepository(db.DB)
+	pickupPointService := services.NewPickupPointService(pickupPointRepo)
+	pickupPointHandler := handlers.NewPickupPointHandler(pickupPointService)
+
+	authLimiter := middleware.NewRateLimiter(middleware.RateLimitPolicy{Limit: 20, Period: time.Minute})
+	apiLimiter := middleware.NewRateLimiter(middleware.RateLimitPolicy{Limit: 600, Period: time.Minute})
+
+	// Запросы идут через прокси Next.js, поэтому адрес клиента берётся
+	// из X-Forwarded-For, но только от перечисленных в TRUSTED_PROXIES подсетей
+	trustedProxies, err := middleware.NewTrustedProxies(cfg.Server.TrustedProxies)
+	if err != nil {
+		fatal("Ошибка в TRUSTED_PROXIES", err)
+	}
+	if len(cfg.Server.TrustedProxies) == 0 {
+		slog.Warn("TRUSTED_PROXIES не задан: за прокси все клиенты получат его адрес, " +
+			"лимиты запросов и блокировка входа по IP станут общими для всех")
+	}
 
 	// Create router
 	router := mux.NewRouter()
+	router.Use(middleware.Metrics)
+	router.Use(trustedProxies.Middleware)
+	router.Use(middleware.RequestID)
+	router.Use(middleware.Language)
+	router.Use(middleware.Tracing)
+	router.Use(middleware.AccessLog)
 
-	// Получаем разрешенные origins из переменной окружения
-	allowedOriginsStr := os.Getenv("ALLOWED_ORIGINS")
-	if allowedOriginsStr == "" {
-		allowedOriginsStr = "http://localhost:3000,https://practice-2025.vercel.app"
-	}
-	allowedOrigins := strings.Split(allowedOriginsStr, ",")
+	allowedOrigins := cfg.Server.AllowedOrigins
 
 	// CORS middleware
 	router.Use(func(next http.Handler) http.Handler {
@@ -74,12 +170,13 @@ func main() {
 				}
 
 				if !allowed {
-					log.Printf("Blocked request from unauthorized origin: %s", origin)
+					logging.FromContext(r.Context()).Warn("Запрос с неразрешённого origin", "origin", origin)
 				}
 			}
 
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Request-ID, traceparent, tracestate")
+			w.Header().Set("Access-Control-Expose-Headers", "RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, RateLimit-Policy, Retry-After, X-Request-ID")
 			w.Header().Set("Access-Control-Allow-Credentials", "true")
 			w.Header().Set("Access-Control-Max-Age", "3600")
 
@@ -92,42 +189,121 @@ func main() {
 		})
 	})
 
+	// общий лимит на все запросы, у отдельных роутов есть свои, более строгие
+	router.Use(apiLimiter.Middleware)
+
 	// публичные роуты
-	router.HandleFunc("/api/auth/register", authHandler.Register).Methods("POST", "OPTIONS")
-	router.HandleFunc("/api/auth/login", authHandler.Login).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/auth/register", authLimiter.Limit(authHandler.Register)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/auth/login", authLimiter.Limit(authHandler.Login)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/auth/refresh", authLimiter.Limit(authHandler.Refresh)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/auth/2fa/verify", twoFactorLimiter.Limit(authHandler.VerifyTwoFactor)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/auth/password/forgot", passwordLimiter.Limit(passwordHandler.ForgotPassword)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/auth/password/reset", passwordLimiter.Limit(passwordHandler.ResetPassword)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/auth/verify-email", authHandler.VerifyEmail).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/tracking/{code}", trackingLimiter.Limit(orderHandler.TrackOrder)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/pickup-points", pickupPointHandler.Search).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/pickup-points/{id:[0-9]+}", pickupPointHandler.GetPickupPoint).Methods("GET", "OPTIONS")
 
 	// защищенные роуты
+	router.HandleFunc("/api/auth/logout", authHandler.LogoutByRefreshToken(authMiddleware.Authenticate(authHandler.Logout))).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/auth/logout-all", authMiddleware.Authenticate(authHandler.LogoutAll)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/auth/verify-email/resend", authMiddleware.Authenticate(verificationLimiter.Limit(authHandler.ResendVerification))).Methods("POST", "OPTIONS")
 	router.HandleFunc("/api/profile", authMiddleware.Authenticate(authHandler.GetProfile)).Methods("GET", "OPTIONS")
 	router.HandleFunc("/api/profile", authMiddleware.Authenticate(authHandler.UpdateProfile)).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/api/profile/password", authMiddleware.Authenticate(passwordHandler.ChangePassword)).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/api/profile/2fa/setup", authMiddleware.Authenticate(twoFactorHandler.Setup)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/profile/2fa/confirm", twoFactorLimiter.Limit(authMiddleware.Authenticate(twoFactorHandler.Confirm))).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/profile/2fa/disable", twoFactorLimiter.Limit(authMiddleware.Authenticate(twoFactorHandler.Disable))).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/profile/sessions", authMiddleware.Authenticate(sessionHandler.ListSessions)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/profile/sessions/{id:[0-9]+}", authMiddleware.Authenticate(sessionHandler.RevokeSession)).Methods("DELETE", "OPTIONS")
+
+	router.HandleFunc("/api/orders", authMiddleware.Authenticate(orderLimiter.Limit(authMiddleware.RequireVerifiedEmail(orderHandler.CreateOrder)))).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/orders", authMiddleware.Authenticate(orderHandler.ListOrders)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/orders/{id:[0-9]+}", authMiddleware.Authenticate(orderHandler.GetOrder)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/orders/{id:[0-9]+}/cancel", authMiddleware.Authenticate(orderHandler.CancelOrder)).Methods("POST", "OPTIONS")
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	router.HandleFunc("/api/cart", authMiddleware.Authenticate(cartHandler.GetCart)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/cart", authMiddleware.Authenticate(cartHandler.ReplaceCart)).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/api/cart/merge", authMiddleware.Authenticate(cartHandler.MergeCart)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/cart/checkout", authMiddleware.Authenticate(orderLimiter.Limit(authMiddleware.RequireVerifiedEmail(cartHandler.Checkout)))).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/cart/items", authMiddleware.Authenticate(cartHandler.AddItem)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/cart/items/{id:[0-9]+}", authMiddleware.Authenticate(cartHandler.UpdateItem)).Methods("PATCH", "OPTIONS")
+	router.HandleFunc("/api/cart/items/{id:[0-9]+}", authMiddleware.Authenticate(cartHandler.RemoveItem)).Methods("DELETE", "OPTIONS")
+
+	// роуты для сотрудников
+	permission := func(h http.HandlerFunc, permissions ...string) http.HandlerFunc {
+		return authMiddleware.Authenticate(authMiddleware.RequirePermission(permissions...)(h))
 	}
+	router.HandleFunc("/api/operator/orders/{id:[0-9]+}/status", permission(orderHandler.ChangeStatus, models.PermOrdersManage, models.PermOrdersDeliver)).Methods("POST", "OPTIONS")
+
+	router.HandleFunc("/api/admin/users", permission(adminUserHandler.ListUsers, models.PermUsersManage)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/admin/users/{id:[0-9]+}", permission(adminUserHandler.GetUser, models.PermUsersManage)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/admin/users/{id:[0-9]+}/block", permission(adminUserHandler.BlockUser, models.PermUsersManage)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/admin/users/{id:[0-9]+}/unblock", permission(adminUserHandler.UnblockUser, models.PermUsersManage)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/admin/users/{id:[0-9]+}/role", permission(adminUserHandler.ChangeRole, models.PermUsersManage)).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/api/admin/users/{id:[0-9]+}/password-reset", permission(adminUserHandler.ForcePasswordReset, models.PermUsersManage)).Methods("POST", "OPTIONS")
+
+	router.HandleFunc("/api/admin/pickup-points", permission(pickupPointHandler.AdminListPickupPoints, models.PermPickupPointsManage)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/admin/pickup-points", permission(pickupPointHandler.CreatePickupPoint, models.PermPickupPointsManage)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/admin/pickup-points/{id:[0-9]+}", permission(pickupPointHandler.AdminGetPickupPoint, models.PermPickupPointsManage)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/admin/pickup-points/{id:[0-9]+}", permission(pickupPointHandler.UpdatePickupPoint, models.PermPickupPointsManage)).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/api/admin/pickup-points/{id:[0-9]+}", permission(pickupPointHandler.DeletePickupPoint, models.PermPickupPointsManage)).Methods("DELETE", "OPTIONS")
+
+	// ответы на неизвестные роуты в том же формате, что и остальные ошибки API
+	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apperror.Write(w, r, apperror.ErrNotFound)
+	})
+	router.MethodNotAllowedHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apperror.Write(w, r, apperror.ErrMethodNotAllowed)
+	})
 
-	// Определяем режим работы: HTTP или HTTPS
-	useHTTPS := os.Getenv("USE_HTTPS")
-	certFile := os.Getenv("CERT_FILE")
-	keyFile := os.Getenv("KEY_FILE")
+	// пробы оркестратора не проходят через CORS и лимиты запросов
+	healthHandler := handlers.NewHealthHandler(db.NewPingCheck(db.DB), db.NewMigrationCheck(migrator))
+	rootMux := http.NewServeMux()
+	rootMux.HandleFunc("/healthz", healthHandler.Live)
+	rootMux.HandleFunc("/readyz", healthHandler.Ready)
+	rootMux.HandleFunc("/version", healthHandler.Version)
+	rootMux.Handle("/metrics", handlers.NewMetricsHandler(cfg.Server.MetricsToken))
+	rootMux.Handle("/", router)
 
-	if useHTTPS == "true" && certFile != "" && keyFile != "" {
-		// Создаем TLS конфигурацию
-		tlsConfig := &tls.Config{
+	server := &http.Server{
+		Addr:              ":" + strconv.Itoa(cfg.Server.Port),
+		Handler:           rootMux,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+	if cfg.Server.UseHTTPS {
+		server.TLSConfig = &tls.Config{
 			MinVersion: tls.VersionTLS12,
 		}
+	}
 
-		// Создаем HTTPS сервер
-		server := &http.Server{
-			Addr:      ":" + port,
-			Handler:   router,
-			TLSConfig: tlsConfig,
-		}
+	serveErr := serve(server, cfg.Server, healthHandler)
 
-		fmt.Printf("HTTPS Server is running on port %s...\n", port)
-		log.Fatal(server.ListenAndServeTLS(certFile, keyFile))
-	} else {
-		// Запускаем HTTP сервер
-		fmt.Printf("HTTP Server is running on port %s...\n", port)
-		log.Fatal(http.ListenAndServe(":"+port, router))
+	// фоновые задачи останавливаются после того, как доработали все запросы
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	sessionActivity.Stop()
+	if err := mailQueue.Close(ctx); err != nil {
+		slog.Warn("Не все письма успели отправиться", "error", err)
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Warn("Не все трассы успели отправиться", "error", err)
+	}
+	cancel()
+	if err := db.DB.Close(); err != nil {
+		slog.Error("Ошибка при закрытии соединений с базой данных", "error", err)
 	}
+
+	if serveErr != nil {
+		fatal("Ошибка HTTP-сервера", serveErr)
+	}
+	slog.Info("Сервер остановлен")
+}
+
+// fatal пишет ошибку в лог и завершает процесс
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "delivery"
+
+// Результаты входа для LoginAttempts
+const (
+	LoginSuccess   = "success"
+	LoginFailure   = "failure"
+	LoginLocked    = "locked"
+	LoginBlocked   = "blocked"
+	LoginTwoFactor = "two_factor_required"
+)
+
+// Источники заказа для OrdersCreated
+const (
+	OrderSourceDirect = "direct"
+	OrderSourceCart   = "cart"
+)
+
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Количество HTTP-запросов по шаблону маршрута, методу и статусу.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Время обработки HTTP-запросов.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	HTTPInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "Количество запросов, обрабатываемых в данный момент.",
+	})
+
+	// bcrypt намеренно медленный, рост этой метрики первым покажет нехватку CPU
+	PasswordHashDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "password_hash_duration_seconds",
+		Help:      "Время вычисления и проверки bcrypt-хешей паролей.",
+		Buckets:   []float64{0.05, 0.1, 0.2, 0.3, 0.5, 0.75, 1, 2},
+	}, []string{"operation"})
+
+	LoginAttempts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "login_attempts_total",
+		Help:      "Попытки входа по результату.",
+	}, []string{"result"})
+
+	OrdersCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orders_created_total",
+		Help:      "Созданные заказы по источнику.",
+	}, []string{"source"})
+)
+
+// RegisterDB публикует статистику пула соединений database/sql
+func RegisterDB(db *sql.DB, name string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// ObservePasswordHash записывает длительность операции с bcrypt:
+// defer metrics.ObservePasswordHash("compare", time.Now())
+func ObservePasswordHash(operation string, start time.Time) {
+	PasswordHashDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
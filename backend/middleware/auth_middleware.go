@@ -2,46 +2,139 @@ package middleware
 
 import (
 	"context"
+	"delivery-service/apperror"
+	"delivery-service/config"
+	"delivery-service/i18n"
+	"delivery-service/models"
 	"delivery-service/services"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 )
 
 type AuthMiddleware struct {
-	authService *services.AuthService
+	authService          *services.AuthService
+	activity             *services.SessionActivity
+	requireVerifiedEmail bool
 }
 
-func NewAuthMiddleware(authService *services.AuthService) *AuthMiddleware {
-	return &AuthMiddleware{authService: authService}
+func NewAuthMiddleware(cfg *config.Config, authService *services.AuthService, activity *services.SessionActivity) *AuthMiddleware {
+	return &AuthMiddleware{
+		authService:          authService,
+		activity:             activity,
+		requireVerifiedEmail: cfg.Auth.RequireVerifiedEmail,
+	}
 }
 
 func (m *AuthMiddleware) Authenticate(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			http.Error(w, "отсутствует токен авторизации", http.StatusUnauthorized)
+			apperror.Write(w, r, apperror.New(http.StatusUnauthorized, "missing_token", "отсутствует токен авторизации"))
 			return
 		}
 
 		tokenParts := strings.Split(authHeader, " ")
 		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			http.Error(w, "неверный формат токена", http.StatusUnauthorized)
+			apperror.Write(w, r, apperror.New(http.StatusUnauthorized, "malformed_token", "неверный формат токена"))
 			return
 		}
 
-		user, err := m.authService.ValidateToken(tokenParts[1])
+		user, sessionID, err := m.authService.ValidateToken(r.Context(), tokenParts[1])
 		if err != nil {
-			http.Error(w, "недействительный токен", http.StatusUnauthorized)
+			if errors.Is(err, services.ErrUserBlocked) {
+				apperror.Write(w, r, err)
+				return
+			}
+			apperror.Write(w, r, services.ErrInvalidToken)
 			return
 		}
 
+		m.activity.Touch(sessionID)
+
 		ctx := context.WithValue(r.Context(), "user", user)
+		ctx = i18n.WithLanguage(ctx, i18n.Preferred(user.Language, i18n.FromContext(ctx)))
 		ctx = context.WithValue(ctx, "userID", user.ID)
+		ctx = context.WithValue(ctx, "sessionID", sessionID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	}
 }
 
+// RequireRole пропускает пользователей с одной из перечисленных ролей.
+// Роль берётся из БД при проверке токена, поэтому понижение прав
+// действует сразу, не дожидаясь истечения токена. Используется после Authenticate.
+func (m *AuthMiddleware) RequireRole(roles ...string) func(http.HandlerFunc) http.HandlerFunc {
+	allowed := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		allowed[role] = true
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			user, ok := r.Context().Value("user").(*models.User)
+			if !ok || user == nil {
+				apperror.Write(w, r, apperror.ErrUnauthorized)
+				return
+			}
+
+			if !allowed[user.Role] {
+				apperror.Write(w, r, apperror.ErrForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+	}
+}
+
+// RequirePermission пропускает пользователей, роль которых даёт хотя бы одно
+// из перечисленных прав. Используется после Authenticate.
+func (m *AuthMiddleware) RequirePermission(permissions ...string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			user, ok := r.Context().Value("user").(*models.User)
+			if !ok || user == nil {
+				apperror.Write(w, r, apperror.ErrUnauthorized)
+				return
+			}
+
+			for _, permission := range permissions {
+				if services.HasPermission(user.Role, permission) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			apperror.Write(w, r, apperror.ErrForbidden)
+		}
+	}
+}
+
+// RequireVerifiedEmail не пускает пользователей с неподтверждённым email,
+// если это включено через REQUIRE_VERIFIED_EMAIL. Используется после Authenticate.
+func (m *AuthMiddleware) RequireVerifiedEmail(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !m.requireVerifiedEmail {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		user, ok := r.Context().Value("user").(*models.User)
+		if !ok || user == nil {
+			apperror.Write(w, r, apperror.ErrUnauthorized)
+			return
+		}
+
+		if user.EmailVerifiedAt == nil {
+			apperror.Write(w, r, apperror.New(http.StatusForbidden, "email_not_verified", "подтвердите email, чтобы оформлять заказы"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+}
+
 func SendJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"delivery-service/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequirePermission(t *testing.T) {
+	m := &AuthMiddleware{}
+	handler := m.RequirePermission(models.PermOrdersManage, models.PermOrdersDeliver)(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name string
+		user *models.User
+		want int
+	}{
+		{"оператор", &models.User{Role: models.RoleOperator}, http.StatusNoContent},
+		// достаточно одного из перечисленных прав
+		{"курьер", &models.User{Role: models.RoleCourier}, http.StatusNoContent},
+		{"клиент", &models.User{Role: models.RoleCustomer}, http.StatusForbidden},
+		{"без пользователя", nil, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/api/orders/1/status", nil)
+			if tt.user != nil {
+				req = req.WithContext(context.WithValue(req.Context(), "user", tt.user))
+			}
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	m := &AuthMiddleware{}
+	handler := m.RequireRole(models.RoleAdmin)(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	for role, want := range map[string]int{
+		models.RoleAdmin:    http.StatusNoContent,
+		models.RoleOperator: http.StatusForbidden,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/users", nil)
+		req = req.WithContext(context.WithValue(req.Context(), "user", &models.User{Role: role}))
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		if rec.Code != want {
+			t.Errorf("role %q: status = %d, want %d", role, rec.Code, want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ClientIP возвращает адрес клиента без порта. За доверенным прокси
+// адрес уже подставлен из X-Forwarded-For middleware TrustedProxies.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// TrustedProxies определяет настоящий адрес клиента по X-Forwarded-For,
+// но только если запрос пришёл от доверенного прокси. Иначе заголовок
+// игнорируется: клиент может прислать его сам и подделать свой IP.
+type TrustedProxies struct {
+	networks []*net.IPNet
+}
+
+// NewTrustedProxies принимает список CIDR или отдельных адресов
+func NewTrustedProxies(cidrs []string) (*TrustedProxies, error) {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		if !strings.Contains(cidr, "/") {
+			if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("некорректная подсеть доверенного прокси %q: %w", cidr, err)
+		}
+		networks = append(networks, network)
+	}
+	return &TrustedProxies{networks: networks}, nil
+}
+
+// Middleware подменяет RemoteAddr адресом клиента, чтобы ClientIP
+// во всех обработчиках возвращал его без дополнительных проверок.
+// Если адрес клиента узнать нельзя, остаётся адрес соединения: заголовок
+// от клиента не должен ни менять его адрес, ни ослаблять ограничения по IP.
+func (p *TrustedProxies) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if remote := net.ParseIP(ClientIP(r)); remote != nil && p.trusted(remote) {
+			if ip := p.clientIP(r); ip != "" {
+				_, port, err := net.SplitHostPort(r.RemoteAddr)
+				if err != nil {
+					port = "0"
+				}
+				r.RemoteAddr = net.JoinHostPort(ip, port)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP проходит X-Forwarded-For справа налево, пропуская доверенные
+// прокси. Первый недоверенный адрес и есть клиент.
+func (p *TrustedProxies) clientIP(r *http.Request) string {
+	hops := make([]string, 0)
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+
+	client := ""
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(hops[i]))
+		if ip == nil {
+			break
+		}
+		client = ip.String()
+		if !p.trusted(ip) {
+			break
+		}
+	}
+	return client
+}
+
+func (p *TrustedProxies) trusted(ip net.IP) bool {
+	for _, network := range p.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
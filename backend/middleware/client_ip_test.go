@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTrustedProxies(t *testing.T) {
+	proxies, err := NewTrustedProxies([]string{"10.0.0.0/8", " 127.0.0.1 ", "::1", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		xff        []string
+		wantIP     string
+	}{
+		{"прямой запрос", "203.0.113.9:5000", nil, "203.0.113.9"},
+		// заголовок от недоверенного адреса ничего не меняет
+		{"недоверенный прокси", "203.0.113.9:5000", []string{"1.2.3.4"}, "203.0.113.9"},
+		{"доверенный прокси", "10.0.0.2:5000", []string{"1.2.3.4"}, "1.2.3.4"},
+		{"цепочка прокси", "10.0.0.2:5000", []string{"6.6.6.6, 1.2.3.4, 10.0.0.3"}, "1.2.3.4"},
+		{"подделанное начало цепочки", "127.0.0.1:5000", []string{"garbage, 1.2.3.4"}, "1.2.3.4"},
+		{"несколько заголовков", "10.0.0.2:5000", []string{"1.2.3.4", "5.6.7.8"}, "5.6.7.8"},
+		{"только доверенные адреса", "10.0.0.2:5000", []string{"10.0.0.5, 10.0.0.4"}, "10.0.0.5"},
+		{"IPv6", "[::1]:5000", []string{"2001:db8::1"}, "2001:db8::1"},
+		{"доверенный прокси без адреса клиента", "10.0.0.2:5000", nil, "10.0.0.2"},
+		{"некорректный адрес клиента", "10.0.0.2:5000", []string{"garbage"}, "10.0.0.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.xff {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+
+			var gotIP string
+			proxies.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotIP = ClientIP(r)
+			})).ServeHTTP(httptest.NewRecorder(), req)
+
+			if gotIP != tt.wantIP {
+				t.Errorf("ClientIP = %q, want %q", gotIP, tt.wantIP)
+			}
+		})
+	}
+}
+
+func TestNewTrustedProxiesInvalid(t *testing.T) {
+	for _, cidr := range []string{"proxy.local", "10.0.0.0/33", "300.1.1.1"} {
+		if _, err := NewTrustedProxies([]string{cidr}); err == nil {
+			t.Errorf("NewTrustedProxies(%q) accepted", cidr)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"delivery-service/i18n"
+	"net/http"
+)
+
+// Language выбирает язык ответов по Accept-Language. Для авторизованных
+// запросов Authenticate заменяет его языком из профиля пользователя.
+func Language(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := i18n.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+		next.ServeHTTP(w, r.WithContext(i18n.WithLanguage(r.Context(), lang)))
+	})
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"delivery-service/metrics"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsRouteTemplate(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(Metrics)
+	router.HandleFunc("/api/orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}).Methods(http.MethodGet)
+	// без явного WriteHeader статус считается 200
+	router.HandleFunc("/api/cart", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	}).Methods(http.MethodGet)
+
+	orders := metrics.HTTPRequests.WithLabelValues(http.MethodGet, "/api/orders/{id}", "404")
+	cart := metrics.HTTPRequests.WithLabelValues(http.MethodGet, "/api/cart", "200")
+	ordersBefore, cartBefore := testutil.ToFloat64(orders), testutil.ToFloat64(cart)
+
+	for _, path := range []string{"/api/orders/1", "/api/orders/2", "/api/cart"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	// запросы к разным заказам попадают в один ряд
+	if got := testutil.ToFloat64(orders) - ordersBefore; got != 2 {
+		t.Errorf("orders requests = %g, want 2", got)
+	}
+	if got := testutil.ToFloat64(cart) - cartBefore; got != 1 {
+		t.Errorf("cart requests = %g, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.HTTPInFlight); got != 0 {
+		t.Errorf("in flight = %g after requests finished, want 0", got)
+	}
+}
+
+func TestRouteTemplateUnmatched(t *testing.T) {
+	if got := routeTemplate(httptest.NewRequest(http.MethodGet, "/unknown/42", nil)); got != "unmatched" {
+		t.Errorf("routeTemplate() = %q, want unmatched", got)
+	}
+}
@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"delivery-service/apperror"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitKey определяет, чей лимит расходует запрос
+type RateLimitKey func(r *http.Request) string
+
+// KeyByIP - лимит на IP-адрес клиента
+func KeyByIP(r *http.Request) string {
+	return "ip:" + ClientIP(r)
+}
+
+// KeyByUser - лимит на пользователя, для анонимных запросов - на IP.
+// Лимитер должен стоять после Authenticate.
+func KeyByUser(r *http.Request) string {
+	if userID, ok := r.Context().Value("userID").(int64); ok && userID > 0 {
+		return "user:" + strconv.FormatInt(userID, 10)
+	}
+	return KeyByIP(r)
+}
+
+// KeyByAPIKey - отдельный лимит для каждого ключа партнёра из keys.
+// Запросы без ключа или с неизвестным ключом считаются по IP, иначе
+// перебором ключей можно было бы обойти лимит. Ключи сравниваются
+// за постоянное время, в памяти хранятся только их хеши.
+func KeyByAPIKey(keys []string) RateLimitKey {
+	hashes := make([][sha256.Size]byte, 0, len(keys))
+	for _, key := range keys {
+		if key != "" {
+			hashes = append(hashes, sha256.Sum256([]byte(key)))
+		}
+	}
+	return func(r *http.Request) string {
+		apiKey := r.Header.Get("X-API-Key")
+		if apiKey == "" {
+			return KeyByIP(r)
+		}
+		sum := sha256.Sum256([]byte(apiKey))
+		known := 0
+		for _, hash := range hashes {
+			known |= subtle.ConstantTimeCompare(sum[:], hash[:])
+		}
+		if known == 0 {
+			return KeyByIP(r)
+		}
+		return "key:" + hex.EncodeToString(sum[:8])
+	}
+}
+
+// RateLimitPolicy - ведро на Limit запросов, которое полностью
+// восстанавливается за Period
+type RateLimitPolicy struct {
+	Limit  int
+	Period time.Duration
+	Key    RateLimitKey
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// RateLimiter ограничивает частоту запросов по алгоритму token bucket
+// и сообщает клиенту остаток лимита в заголовках RateLimit-*
+type RateLimiter struct {
+	policy RateLimitPolicy
+	// скорость пополнения, токенов в секунду
+	rate float64
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func NewRateLimiter(policy RateLimitPolicy) *RateLimiter {
+	if policy.Limit <= 0 || policy.Period <= 0 {
+		panic("rate limit and period must be positive")
+	}
+	if policy.Key == nil {
+		policy.Key = KeyByIP
+	}
+	return &RateLimiter{
+		policy:    policy,
+		rate:      float64(policy.Limit) / policy.Period.Seconds(),
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+func (l *RateLimiter) Limit(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if l.allow(w, r) {
+			next.ServeHTTP(w, r)
+		}
+	}
+}
+
+// Middleware - то же, что Limit, для router.Use
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if l.allow(w, r) {
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+func (l *RateLimiter) allow(w http.ResponseWriter, r *http.Request) bool {
+	allowed, remaining, reset, retryAfter := l.take(l.policy.Key(r), time.Now())
+
+	// заголовки по draft-ietf-httpapi-ratelimit-headers
+	w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", l.policy.Limit, int(l.policy.Period.Seconds())))
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(l.policy.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(reset)))
+
+	if !allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(retryAfter)))
+		apperror.Write(w, r, apperror.ErrRateLimited)
+		return false
+	}
+	return true
+}
+
+// take списывает токен и возвращает остаток, время до полного
+// восстановления ведра и, при отказе, время до появления токена
+func (l *RateLimiter) take(key string, now time.Time) (bool, int, time.Duration, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > l.policy.Period {
+		l.sweep(now)
+	}
+
+	limit := float64(l.policy.Limit)
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: limit, updated: now}
+		l.buckets[key] = bucket
+	}
+
+	bucket.tokens = math.Min(limit, bucket.tokens+now.Sub(bucket.updated).Seconds()*l.rate)
+	bucket.updated = now
+
+	allowed := bucket.tokens >= 1
+	var retryAfter time.Duration
+	if allowed {
+		bucket.tokens--
+	} else {
+		retryAfter = l.duration(1 - bucket.tokens)
+	}
+
+	return allowed, int(bucket.tokens), l.duration(limit - bucket.tokens), retryAfter
+}
+
+func (l *RateLimiter) duration(tokens float64) time.Duration {
+	return time.Duration(tokens / l.rate * float64(time.Second))
+}
+
+// sweep удаляет вёдра, которые уже восстановились полностью:
+// они ничем не отличаются от новых
+func (l *RateLimiter) sweep(now time.Time) {
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.updated) >= l.policy.Period {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterRefill(t *testing.T) {
+	// 2 запроса за 10 секунд: токен восстанавливается за 5 секунд
+	limiter := NewRateLimiter(RateLimitPolicy{Limit: 2, Period: 10 * time.Second})
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		at         time.Duration
+		allowed    bool
+		remaining  int
+		retryAfter time.Duration
+	}{
+		{0, true, 1, 0},
+		{0, true, 0, 0},
+		{0, false, 0, 5 * time.Second},
+		{2500 * time.Millisecond, false, 0, 2500 * time.Millisecond},
+		{5 * time.Second, true, 0, 0},
+		{6 * time.Second, false, 0, 4 * time.Second},
+		// за полный период ведро восстанавливается, но не сверх лимита
+		{time.Minute, true, 1, 0},
+	}
+	for i, tt := range tests {
+		allowed, remaining, _, retryAfter := limiter.take("ip:1.2.3.4", start.Add(tt.at))
+		if allowed != tt.allowed || remaining != tt.remaining {
+			t.Errorf("step %d: allowed %v remaining %d, want %v %d", i, allowed, remaining, tt.allowed, tt.remaining)
+		}
+		if diff := retryAfter - tt.retryAfter; diff < -time.Millisecond || diff > time.Millisecond {
+			t.Errorf("step %d: retry after %s, want %s", i, retryAfter, tt.retryAfter)
+		}
+	}
+
+	// у другого ключа своё ведро
+	if allowed, _, _, _ := limiter.take("ip:5.6.7.8", start); !allowed {
+		t.Error("separate key was limited")
+	}
+}
+
+func TestRateLimiterHeaders(t *testing.T) {
+	limiter := NewRateLimiter(RateLimitPolicy{Limit: 1, Period: time.Minute})
+	handler := limiter.Limit(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		status     int
+		remaining  string
+		retryAfter string
+	}{
+		{http.StatusNoContent, "0", ""},
+		{http.StatusTooManyRequests, "0", "60"},
+	}
+	for i, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/tracking/X", nil)
+		req.RemoteAddr = "203.0.113.9:5000"
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+
+		if rec.Code != tt.status {
+			t.Fatalf("request %d: status %d, want %d", i, rec.Code, tt.status)
+		}
+		if got := rec.Header().Get("RateLimit-Policy"); got != "1;w=60" {
+			t.Errorf("request %d: RateLimit-Policy %q", i, got)
+		}
+		if got := rec.Header().Get("RateLimit-Remaining"); got != tt.remaining {
+			t.Errorf("request %d: RateLimit-Remaining %q, want %q", i, got, tt.remaining)
+		}
+		if got := rec.Header().Get("Retry-After"); got != tt.retryAfter {
+			t.Errorf("request %d: Retry-After %q, want %q", i, got, tt.retryAfter)
+		}
+		if tt.status == http.StatusTooManyRequests && !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/problem+json") {
+			t.Errorf("request %d: Content-Type %q", i, rec.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestKeyByAPIKey(t *testing.T) {
+	key := KeyByAPIKey([]string{"partner-secret", ""})
+
+	tests := []struct {
+		name   string
+		apiKey string
+		prefix string
+	}{
+		{"без ключа", "", "ip:"},
+		{"известный ключ", "partner-secret", "key:"},
+		{"неизвестный ключ", "random-key", "ip:"},
+		{"пустой ключ из настроек не подходит", " ", "ip:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = "203.0.113.9:5000"
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			got := key(req)
+			if !strings.HasPrefix(got, tt.prefix) {
+				t.Errorf("key = %q, want prefix %q", got, tt.prefix)
+			}
+			if strings.Contains(got, "partner-secret") {
+				t.Errorf("key %q contains the API key", got)
+			}
+		})
+	}
+}
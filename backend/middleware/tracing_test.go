@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans подменяет глобальный провайдер и формат заголовков на время теста
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return recorder
+}
+
+func TestTracing(t *testing.T) {
+	const (
+		traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentSpanID = "00f067aa0ba902b7"
+	)
+
+	tests := []struct {
+		name        string
+		traceparent string
+		status      int
+		wantCode    codes.Code
+	}{
+		{"новая трасса", "", http.StatusOK, codes.Unset},
+		// трасса продолжается из заголовка прокси
+		{"продолжение трассы", "00-" + traceID + "-" + parentSpanID + "-01", http.StatusOK, codes.Unset},
+		// ошибки клиента не отмечают span ошибкой
+		{"ошибка клиента", "", http.StatusNotFound, codes.Unset},
+		{"ошибка сервера", "", http.StatusBadGateway, codes.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := recordSpans(t)
+
+			router := mux.NewRouter()
+			router.Use(Tracing)
+			router.HandleFunc("/api/orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/orders/42", nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("got %d spans, want 1", len(spans))
+			}
+			span := spans[0]
+			if span.Name() != "GET /api/orders/{id}" {
+				t.Errorf("name = %q", span.Name())
+			}
+			if span.Status().Code != tt.wantCode {
+				t.Errorf("status = %v, want %v", span.Status().Code, tt.wantCode)
+			}
+			if tt.traceparent != "" {
+				if got := span.SpanContext().TraceID().String(); got != traceID {
+					t.Errorf("trace id = %s, want %s", got, traceID)
+				}
+				if got := span.Parent().SpanID().String(); got != parentSpanID {
+					t.Errorf("parent span id = %s, want %s", got, parentSpanID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+// UserFilter - параметры поиска пользователей в админке
+type UserFilter struct {
+	Email       string
+	Name        string
+	City        string
+	Role        string
+	Blocked     *bool
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Limit       int
+	Offset      int
+}
+
+type UserListResponse struct {
+	Users   []*User `json:"users"`
+	Total   int     `json:"total"`
+	Page    int     `json:"page"`
+	PerPage int     `json:"per_page"`
+}
+
+type BlockUserRequest struct {
+	Reason *string `json:"reason,omitempty"`
+}
+
+type ChangeRoleRequest struct {
+	Role string `json:"role"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// Действия, записываемые в журнал аудита
+const (
+	AuditPasswordChanged = "password_changed"
+	AuditPasswordReset   = "password_reset"
+	AuditTwoFactorOn     = "two_factor_enabled"
+	AuditTwoFactorOff    = "two_factor_disabled"
+	AuditUserBlocked     = "user_blocked"
+	AuditUserUnblocked   = "user_unblocked"
+	AuditRoleChanged     = "role_changed"
+	AuditPasswordForced  = "password_reset_forced"
+)
+
+type AuditEntry struct {
+	ID        int64                  `json:"id"`
+	UserID    *int64                 `json:"user_id,omitempty"`
+	ActorID   *int64                 `json:"actor_id,omitempty"`
+	Action    string                 `json:"action"`
+	IP        string                 `json:"ip"`
+	UserAgent string                 `json:"user_agent"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+type CartItem struct {
+	ID          int64     `json:"id"`
+	Marketplace string    `json:"marketplace"`
+	Link        string    `json:"link"`
+	Quantity    int       `json:"quantity"`
+	Size        *string   `json:"size,omitempty"`
+	Color       *string   `json:"color,omitempty"`
+	Notes       *string   `json:"notes,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type Cart struct {
+	Items              []CartItem `json:"items"`
+	TotalItems         int        `json:"total_items"`
+	UniqueMarketplaces int        `json:"unique_marketplaces"`
+}
+
+// CartItemsRequest используется для замены корзины целиком (PUT /api/cart)
+// и для слияния анонимной корзины после входа (POST /api/cart/merge)
+type CartItemsRequest struct {
+	Items []OrderItemRequest `json:"items"`
+}
+
+type UpdateCartItemRequest struct {
+	Quantity *int    `json:"quantity,omitempty"`
+	Size     *string `json:"size,omitempty"`
+	Color    *string `json:"color,omitempty"`
+	Notes    *string `json:"notes,omitempty"`
+}
+
+type CheckoutRequest struct {
+	DeliveryDetails DeliveryDetails `json:"delivery_details"`
+}
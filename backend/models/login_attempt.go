@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// LockoutPolicy - правила временной блокировки входа для одного типа ключа
+// (IP-адрес или учётная запись)
+type LockoutPolicy struct {
+	// Limit неудачных попыток за Window приводит к блокировке
+	Limit  int
+	Window time.Duration
+	// первая блокировка длится BaseLockout, каждая следующая вдвое дольше, но не более MaxLockout
+	BaseLockout time.Duration
+	MaxLockout  time.Duration
+	// если блокировок не было ResetAfter, счётчик блокировок начинается заново
+	ResetAfter time.Duration
+}
+
+// Backoff возвращает длительность блокировки с номером lockouts (с 1)
+func (p LockoutPolicy) Backoff(lockouts int) time.Duration {
+	duration := p.BaseLockout
+	for i := 1; i < lockouts && duration < p.MaxLockout; i++ {
+		duration *= 2
+	}
+	if duration > p.MaxLockout {
+		duration = p.MaxLockout
+	}
+	return duration
+}
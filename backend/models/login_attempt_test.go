@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockoutPolicyBackoff(t *testing.T) {
+	policy := LockoutPolicy{BaseLockout: time.Minute, MaxLockout: time.Hour}
+	tests := []struct {
+		lockouts int
+		want     time.Duration
+	}{
+		{0, time.Minute},
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{6, 32 * time.Minute},
+		{7, time.Hour},
+		{100, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := policy.Backoff(tt.lockouts); got != tt.want {
+			t.Errorf("Backoff(%d) = %s, want %s", tt.lockouts, got, tt.want)
+		}
+	}
+}
+
+func TestLockoutPolicyBackoffBaseAboveMax(t *testing.T) {
+	policy := LockoutPolicy{BaseLockout: 2 * time.Hour, MaxLockout: time.Hour}
+	if got := policy.Backoff(1); got != time.Hour {
+		t.Errorf("Backoff(1) = %s, want %s", got, time.Hour)
+	}
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	OrderStatusCreated        = "created"
+	OrderStatusAccepted       = "accepted"
+	OrderStatusPurchased      = "purchased_at_marketplace"
+	OrderStatusAtWarehouse    = "at_warehouse"
+	OrderStatusInTransit      = "in_transit"
+	OrderStatusOutForDelivery = "out_for_delivery"
+	OrderStatusReadyForPickup = "ready_for_pickup"
+	OrderStatusDelivered      = "delivered"
+	OrderStatusCancelled      = "cancelled"
+	OrderStatusReturned       = "returned"
+)
+
+type Order struct {
+	ID              int64              `json:"id"`
+	UserID          int64              `json:"user_id"`
+	TrackingCode    string             `json:"tracking_code"`
+	Status          string             `json:"status"`
+	Items           []OrderItem        `json:"items"`
+	DeliveryDetails DeliveryDetails    `json:"delivery_details"`
+	History         []OrderStatusEvent `json:"history,omitempty"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+// OrderStatusEvent - запись о смене статуса заказа
+type OrderStatusEvent struct {
+	ID         int64     `json:"id"`
+	OrderID    int64     `json:"order_id"`
+	FromStatus *string   `json:"from_status,omitempty"`
+	ToStatus   string    `json:"to_status"`
+	ActorID    *int64    `json:"actor_id,omitempty"`
+	Location   *string   `json:"location,omitempty"`
+	Comment    *string   `json:"comment,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type OrderItem struct {
+	ID          int64   `json:"id"`
+	OrderID     int64   `json:"order_id"`
+	Marketplace string  `json:"marketplace"`
+	Link        string  `json:"link"`
+	Quantity    int     `json:"quantity"`
+	Size        *string `json:"size,omitempty"`
+	Color       *string `json:"color,omitempty"`
+	Notes       *string `json:"notes,omitempty"`
+}
+
+type DeliveryDetails struct {
+	Address string `json:"address"`
+	Date    string `json:"date"`
+	Time    string `json:"time"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+type CreateOrderRequest struct {
+	Items           []OrderItemRequest `json:"items"`
+	DeliveryDetails DeliveryDetails    `json:"delivery_details"`
+}
+
+type OrderItemRequest struct {
+	Marketplace string  `json:"marketplace"`
+	Link        string  `json:"link"`
+	Quantity    int     `json:"quantity"`
+	Size        *string `json:"size,omitempty"`
+	Color       *string `json:"color,omitempty"`
+	Notes       *string `json:"notes,omitempty"`
+}
+
+type ChangeOrderStatusRequest struct {
+	Status   string  `json:"status"`
+	Location *string `json:"location,omitempty"`
+	Comment  *string `json:"comment,omitempty"`
+}
+
+type CancelOrderRequest struct {
+	Comment *string `json:"comment,omitempty"`
+}
+
+// TrackingInfo - публичное представление заказа для поиска по трек-номеру,
+// без адреса, контактов и идентификаторов пользователей
+type TrackingInfo struct {
+	TrackingCode string          `json:"tracking_code"`
+	Status       string          `json:"status"`
+	DeliveryDate string          `json:"delivery_date"`
+	ItemsCount   int             `json:"items_count"`
+	CreatedAt    time.Time       `json:"created_at"`
+	History      []TrackingEvent `json:"history"`
+}
+
+type TrackingEvent struct {
+	Status    string    `json:"status"`
+	Location  *string   `json:"location,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
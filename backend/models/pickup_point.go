@@ -0,0 +1,84 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Дни недели в расписании пункта выдачи
+var Weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+type PickupPoint struct {
+	ID             int64        `json:"id"`
+	Name           string       `json:"name"`
+	Address        string       `json:"address"`
+	City           string       `json:"city"`
+	Latitude       float64      `json:"latitude"`
+	Longitude      float64      `json:"longitude"`
+	Timezone       string       `json:"timezone"`
+	WorkingHours   WorkingHours `json:"working_hours"`
+	Phone          *string      `json:"phone,omitempty"`
+	Capacity       int          `json:"capacity"`
+	IsActive       bool         `json:"is_active"`
+	DistanceMeters *float64     `json:"distance_m,omitempty"`
+	OpenNow        bool         `json:"open_now"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+// DayHours - часы работы в течение дня в формате HH:MM.
+// Если Close <= Open, смена заканчивается на следующий день.
+type DayHours struct {
+	Open  string `json:"open"`
+	Close string `json:"close"`
+}
+
+// WorkingHours - расписание по дням недели (ключи из Weekdays),
+// отсутствующий день означает выходной
+type WorkingHours map[string]DayHours
+
+func (h WorkingHours) Value() (driver.Value, error) {
+	if h == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(h)
+}
+
+func (h *WorkingHours) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	case nil:
+		*h = WorkingHours{}
+		return nil
+	default:
+		return errors.New("unsupported type for working hours")
+	}
+	return json.Unmarshal(data, h)
+}
+
+type PickupPointRequest struct {
+	Name         string       `json:"name"`
+	Address      string       `json:"address"`
+	City         string       `json:"city"`
+	Latitude     *float64     `json:"latitude"`
+	Longitude    *float64     `json:"longitude"`
+	Timezone     string       `json:"timezone"`
+	WorkingHours WorkingHours `json:"working_hours"`
+	Phone        *string      `json:"phone,omitempty"`
+	Capacity     int          `json:"capacity"`
+	IsActive     *bool        `json:"is_active,omitempty"`
+}
+
+// PickupPointQuery - параметры публичного поиска пунктов выдачи
+type PickupPointQuery struct {
+	Latitude     *float64
+	Longitude    *float64
+	RadiusMeters float64
+	Limit        int
+}
@@ -0,0 +1,17 @@
+package models
+
+// Роли пользователей
+const (
+	RoleCustomer = "customer"
+	RoleOperator = "operator"
+	RoleCourier  = "courier"
+	RoleAdmin    = "admin"
+)
+
+// Права, которые проверяет RequirePermission
+const (
+	PermOrdersManage       = "orders:manage"
+	PermOrdersDeliver      = "orders:deliver"
+	PermPickupPointsManage = "pickup_points:manage"
+	PermUsersManage        = "users:manage"
+)
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// Session - сеанс входа пользователя (семейство refresh-токенов одного устройства)
+type Session struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"-"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	Current    bool       `json:"current"`
+}
+
+// ClientInfo - данные клиента, сохраняемые при создании сеанса
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
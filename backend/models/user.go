@@ -4,11 +4,20 @@ import (
 	"time"
 )
 
+// Способы связи для PreferredContact
+const (
+	ContactPhone    = "phone"
+	ContactTelegram = "telegram"
+	ContactWhatsApp = "whatsapp"
+	ContactEmail    = "email"
+)
+
 type User struct {
 	ID               int64      `json:"id"`
 	Name             string     `json:"name"`
 	Email            string     `json:"email"`
 	PasswordHash     string     `json:"-"`
+	Role             string     `json:"role"`
 	Avatar           string     `json:"avatar,omitempty"`
 	Phone            *string    `json:"phone,omitempty"`
 	BirthDate        *time.Time `json:"birth_date,omitempty"`
@@ -21,6 +30,10 @@ type User struct {
 	PreferredContact *string    `json:"preferred_contact,omitempty"`
 	Language         *string    `json:"language,omitempty"`
 	Notifications    bool       `json:"notifications"`
+	EmailVerifiedAt  *time.Time `json:"email_verified_at,omitempty"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+	BlockedAt        *time.Time `json:"blocked_at,omitempty"`
+	BlockedReason    *string    `json:"blocked_reason,omitempty"`
 	CreatedAt        time.Time  `json:"created_at"`
 	UpdatedAt        time.Time  `json:"updated_at"`
 }
@@ -37,9 +50,32 @@ type RegisterRequest struct {
 	ConfirmPassword string `json:"confirm_password"`
 }
 
-type AuthResponse struct {
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token           string `json:"token"`
+	Password        string `json:"password"`
+	ConfirmPassword string `json:"confirm_password"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+	ConfirmPassword string `json:"confirm_password"`
+}
+
+type VerifyEmailRequest struct {
 	Token string `json:"token"`
-	User  *User  `json:"user"`
+}
+
+type AuthResponse struct {
+	Token            string    `json:"token"`
+	TokenExpiresAt   time.Time `json:"token_expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+	User             *User     `json:"user"`
 }
 
 type UpdateUserRequest struct {
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"delivery-service/models"
+	"encoding/json"
+)
+
+const (
+	queryCreateAuditEntry = `
+		INSERT INTO audit_log (user_id, actor_id, action, ip, user_agent, details)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+)
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	if db == nil {
+		panic("database connection is required")
+	}
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) Record(ctx context.Context, entry *models.AuditEntry) error {
+	if entry == nil || entry.Action == "" {
+		return ErrInvalidInput
+	}
+
+	// без деталей передаётся NULL: пустой []byte драйвер отправит как
+	// пустую строку, которую PostgreSQL не примет как JSONB
+	var details interface{}
+	if len(entry.Details) > 0 {
+		data, err := json.Marshal(entry.Details)
+		if err != nil {
+			return err
+		}
+		details = data
+	}
+
+	ctx, span := startQuery(ctx, "AuditRepository.Record", queryCreateAuditEntry)
+	err := r.db.QueryRowContext(ctx,
+		queryCreateAuditEntry,
+		entry.UserID,
+		entry.ActorID,
+		entry.Action,
+		entry.IP,
+		entry.UserAgent,
+		details,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	endQuery(span, err)
+	return err
+}
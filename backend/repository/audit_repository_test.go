@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"delivery-service/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAuditRecord(t *testing.T) {
+	userID := int64(3)
+	createdAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		entry *models.AuditEntry
+		args  []driver.Value
+	}{
+		{
+			// без деталей в базу пишется NULL, а не пустой объект
+			name:  "без деталей",
+			entry: &models.AuditEntry{UserID: &userID, Action: models.AuditPasswordChanged, IP: "203.0.113.5", UserAgent: "curl"},
+			args:  []driver.Value{int64(3), nil, models.AuditPasswordChanged, "203.0.113.5", "curl", nil},
+		},
+		{
+			name:  "с деталями",
+			entry: &models.AuditEntry{UserID: &userID, ActorID: &userID, Action: models.AuditRoleChanged, Details: map[string]interface{}{"role": "admin"}},
+			args:  []driver.Value{int64(3), int64(3), models.AuditRoleChanged, "", "", []byte(`{"role":"admin"}`)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _ := newFakeDB(t, fakeStep{
+				query:   "INSERT INTO audit_log",
+				args:    tt.args,
+				columns: []string{"id", "created_at"},
+				rows:    [][]driver.Value{{int64(11), createdAt}},
+			})
+			if err := NewAuditRepository(db).Record(context.Background(), tt.entry); err != nil {
+				t.Fatal(err)
+			}
+			if tt.entry.ID != 11 || !tt.entry.CreatedAt.Equal(createdAt) {
+				t.Errorf("entry = %+v", tt.entry)
+			}
+		})
+	}
+
+	db, _ := newFakeDB(t)
+	if err := NewAuditRepository(db).Record(context.Background(), &models.AuditEntry{}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("empty action: %v, want ErrInvalidInput", err)
+	}
+}
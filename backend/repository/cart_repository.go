@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"delivery-service/models"
+	"errors"
+)
+
+var (
+	ErrCartItemNotFound = errors.New("cart item not found")
+	ErrCartFull         = errors.New("cart item limit exceeded")
+	ErrCartEmpty        = errors.New("cart is empty")
+)
+
+const (
+	queryGetCartItems = `
+		SELECT id, marketplace, link, quantity, size, color, notes, created_at, updated_at
+		FROM cart_items
+		WHERE user_id = $1
+		ORDER BY id`
+
+	// Вставка выполняется только если в корзине меньше $8 позиций
+	queryAddCartItem = `
+		INSERT INTO cart_items (user_id, marketplace, link, quantity, size, color, notes)
+		SELECT $1, $2, $3, $4, $5, $6, $7
+		WHERE (SELECT COUNT(*) FROM cart_items WHERE user_id = $1) < $8
+		RETURNING id, created_at, updated_at`
+
+	queryUpdateCartItem = `
+		UPDATE cart_items SET
+			quantity = COALESCE($1, quantity),
+			size = COALESCE($2, size),
+			color = COALESCE($3, color),
+			notes = COALESCE($4, notes),
+			updated_at = NOW()
+		WHERE id = $5 AND user_id = $6
+		RETURNING id, marketplace, link, quantity, size, color, notes, created_at, updated_at`
+
+	queryDeleteCartItem = `
+		DELETE FROM cart_items WHERE id = $1 AND user_id = $2`
+
+	queryClearCart = `
+		DELETE FROM cart_items WHERE user_id = $1`
+
+	// Одинаковым считается товар с той же ссылкой, размером и цветом
+	queryMergeCartItem = `
+		UPDATE cart_items SET quantity = quantity + $1, updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM cart_items
+			WHERE user_id = $2 AND marketplace = $3 AND link = $4
+			  AND size IS NOT DISTINCT FROM $5 AND color IS NOT DISTINCT FROM $6
+			ORDER BY id
+			LIMIT 1
+		)`
+
+	// Блокировка корзины пользователя до конца транзакции. Строки через
+	// FOR UPDATE не подходят: у пустой корзины блокировать нечего.
+	queryLockCart = `
+		SELECT pg_advisory_xact_lock(hashtext('cart_items'), $1)`
+)
+
+type CartRepository struct {
+	db *sql.DB
+}
+
+func NewCartRepository(db *sql.DB) *CartRepository {
+	if db == nil {
+		panic("database connection is required")
+	}
+	return &CartRepository{db: db}
+}
+
+func (r *CartRepository) GetItems(ctx context.Context, userID int64) ([]models.CartItem, error) {
+	if userID <= 0 {
+		return nil, ErrInvalidInput
+	}
+
+	ctx, span := startQuery(ctx, "CartRepository.GetItems", queryGetCartItems)
+	items, err := r.getItems(ctx, userID)
+	endQuery(span, err)
+	return items, err
+}
+
+func (r *CartRepository) getItems(ctx context.Context, userID int64) ([]models.CartItem, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetCartItems, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCartItems(rows)
+}
+
+func (r *CartRepository) AddItem(ctx context.Context, userID int64, item *models.CartItem, maxItems int) error {
+	if userID <= 0 || item == nil {
+		return ErrInvalidInput
+	}
+
+	// span охватывает всю транзакцию, включая BEGIN и COMMIT
+	ctx, span := startQuery(ctx, "CartRepository.AddItem", queryAddCartItem)
+	err := r.addItem(ctx, userID, item, maxItems)
+	endQuery(span, err)
+	return err
+}
+
+func (r *CartRepository) addItem(ctx context.Context, userID int64, item *models.CartItem, maxItems int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, queryLockCart, userID); err != nil {
+		return err
+	}
+
+	if err := insertCartItem(ctx, tx, userID, item, maxItems); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *CartRepository) UpdateItem(ctx context.Context, userID, itemID int64, update *models.UpdateCartItemRequest) (*models.CartItem, error) {
+	if userID <= 0 || itemID <= 0 || update == nil {
+		return nil, ErrInvalidInput
+	}
+
+	ctx, span := startQuery(ctx, "CartRepository.UpdateItem", queryUpdateCartItem)
+	row := r.db.QueryRowContext(ctx,
+		queryUpdateCartItem,
+		update.Quantity,
+		update.Size,
+		update.Color,
+		update.Notes,
+		itemID,
+		userID,
+	)
+
+	item, err := scanCartItem(row)
+	endQuery(span, err)
+	if err == sql.ErrNoRows {
+		return nil, ErrCartItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (r *CartRepository) DeleteItem(ctx context.Context, userID, itemID int64) error {
+	if userID <= 0 || itemID <= 0 {
+		return ErrInvalidInput
+	}
+
+	ctx, span := startQuery(ctx, "CartRepository.DeleteItem", queryDeleteCartItem)
+	res, err := r.db.ExecContext(ctx, queryDeleteCartItem, itemID, userID)
+	endQuery(span, err)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrCartItemNotFound
+	}
+	return nil
+}
+
+// ReplaceItems заменяет содержимое корзины одной транзакцией
+func (r *CartRepository) ReplaceItems(ctx context.Context, userID int64, items []models.CartItem, maxItems int) error {
+	if userID <= 0 {
+		return ErrInvalidInput
+	}
+	if len(items) > maxItems {
+		return ErrCartFull
+	}
+
+	ctx, span := startQuery(ctx, "CartRepository.ReplaceItems", queryClearCart)
+	err := r.replaceItems(ctx, userID, items, maxItems)
+	endQuery(span, err)
+	return err
+}
+
+func (r *CartRepository) replaceItems(ctx context.Context, userID int64, items []models.CartItem, maxItems int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, queryLockCart, userID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, queryClearCart, userID); err != nil {
+		return err
+	}
+
+	for i := range items {
+		if err := insertCartItem(ctx, tx, userID, &items[i], maxItems); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// MergeItems добавляет товары анонимной корзины к корзине пользователя:
+// совпадающие позиции суммируются, новые добавляются в конец
+func (r *CartRepository) MergeItems(ctx context.Context, userID int64, items []models.CartItem, maxItems int) error {
+	if userID <= 0 {
+		return ErrInvalidInput
+	}
+
+	ctx, span := startQuery(ctx, "CartRepository.MergeItems", queryMergeCartItem)
+	err := r.mergeItems(ctx, userID, items, maxItems)
+	endQuery(span, err)
+	return err
+}
+
+func (r *CartRepository) mergeItems(ctx context.Context, userID int64, items []models.CartItem, maxItems int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// параллельное слияние с другого устройства не должно задвоить товары
+	if _, err := tx.ExecContext(ctx, queryLockCart, userID); err != nil {
+		return err
+	}
+
+	for i := range items {
+		item := &items[i]
+		res, err := tx.ExecContext(ctx, queryMergeCartItem, item.Quantity, userID, item.Marketplace, item.Link, item.Size, item.Color)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected > 0 {
+			continue
+		}
+
+		if err := insertCartItem(ctx, tx, userID, item, maxItems); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func insertCartItem(ctx context.Context, tx *sql.Tx, userID int64, item *models.CartItem, maxItems int) error {
+	err := tx.QueryRowContext(ctx,
+		queryAddCartItem,
+		userID,
+		item.Marketplace,
+		item.Link,
+		item.Quantity,
+		item.Size,
+		item.Color,
+		item.Notes,
+		maxItems,
+	).Scan(&item.ID, &item.CreatedAt, &item.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrCartFull
+	}
+	return err
+}
+
+func scanCartItems(rows *sql.Rows) ([]models.CartItem, error) {
+	items := make([]models.CartItem, 0)
+	for rows.Next() {
+		item, err := scanCartItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	return items, rows.Err()
+}
+
+func scanCartItem(row rowScanner) (*models.CartItem, error) {
+	var (
+		item  models.CartItem
+		size  sql.NullString
+		color sql.NullString
+		notes sql.NullString
+	)
+	err := row.Scan(
+		&item.ID,
+		&item.Marketplace,
+		&item.Link,
+		&item.Quantity,
+		&size,
+		&color,
+		&notes,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if size.Valid {
+		item.Size = &size.String
+	}
+	if color.Valid {
+		item.Color = &color.String
+	}
+	if notes.Valid {
+		item.Notes = &notes.String
+	}
+	return &item, nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"delivery-service/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMergeCartItems(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	size := "M"
+	items := func() []models.CartItem {
+		return []models.CartItem{
+			{Marketplace: "ozon", Link: "https://ozon.ru/1", Quantity: 2, Size: &size},
+			{Marketplace: "wb", Link: "https://wb.ru/2", Quantity: 1},
+		}
+	}
+	lock := fakeStep{query: "pg_advisory_xact_lock", args: []driver.Value{int64(3)}, affected: 1}
+
+	tests := []struct {
+		name          string
+		steps         []fakeStep
+		wantErr       error
+		wantCommitted bool
+		wantNewID     int64
+	}{
+		{
+			// первый товар уже есть в корзине - количество суммируется, второй добавляется
+			name: "слияние и добавление",
+			steps: []fakeStep{
+				lock,
+				{query: "SET quantity = quantity + $1", args: []driver.Value{int64(2), int64(3), "ozon", "https://ozon.ru/1", "M", nil}, affected: 1},
+				{query: "SET quantity = quantity + $1", args: []driver.Value{int64(1), int64(3), "wb", "https://wb.ru/2", nil, nil}, affected: 0},
+				{
+					query:   "INSERT INTO cart_items",
+					args:    []driver.Value{int64(3), "wb", "https://wb.ru/2", int64(1), nil, nil, nil, int64(50)},
+					columns: []string{"id", "created_at", "updated_at"},
+					rows:    [][]driver.Value{{int64(21), now, now}},
+				},
+			},
+			wantCommitted: true,
+			wantNewID:     21,
+		},
+		{
+			// вставка не выполнена из-за лимита: корзина не меняется
+			name: "корзина заполнена",
+			steps: []fakeStep{
+				lock,
+				{query: "SET quantity = quantity + $1", affected: 0},
+				{query: "INSERT INTO cart_items", columns: []string{"id", "created_at", "updated_at"}},
+			},
+			wantErr: ErrCartFull,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t, tt.steps...)
+			merged := items()
+
+			err := NewCartRepository(db).MergeItems(context.Background(), 3, merged, 50)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MergeItems() = %v, want %v", err, tt.wantErr)
+			}
+			if fake.committed != tt.wantCommitted {
+				t.Errorf("committed = %v, want %v", fake.committed, tt.wantCommitted)
+			}
+			if tt.wantNewID != 0 && merged[1].ID != tt.wantNewID {
+				t.Errorf("new item ID = %d, want %d", merged[1].ID, tt.wantNewID)
+			}
+		})
+	}
+}
+
+func TestReplaceCartItemsLimit(t *testing.T) {
+	db, _ := newFakeDB(t)
+	items := make([]models.CartItem, 3)
+	if err := NewCartRepository(db).ReplaceItems(context.Background(), 3, items, 2); !errors.Is(err, ErrCartFull) {
+		t.Errorf("ReplaceItems() = %v, want ErrCartFull", err)
+	}
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeStep - ожидаемый запрос и ответ на него. Запрос сверяется по подстроке,
+// args - только если заданы.
+type fakeStep struct {
+	query   string
+	args    []driver.Value
+	columns []string
+	rows    [][]driver.Value
+	// affected - результат Exec
+	affected int64
+	err      error
+}
+
+// fakeDB - база, которая отвечает на запросы строго по сценарию.
+// Нужна, чтобы проверять ветвления репозиториев без PostgreSQL.
+type fakeDB struct {
+	t         *testing.T
+	steps     []fakeStep
+	committed bool
+}
+
+func newFakeDB(t *testing.T, steps ...fakeStep) (*sql.DB, *fakeDB) {
+	t.Helper()
+	fake := &fakeDB{t: t, steps: steps}
+	db := sql.OpenDB(fake)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		db.Close()
+		if len(fake.steps) > 0 {
+			t.Errorf("unexecuted queries: %q", fake.steps[0].query)
+		}
+	})
+	return db, fake
+}
+
+func (f *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: f}, nil }
+func (f *fakeDB) Driver() driver.Driver                        { return nil }
+
+func (f *fakeDB) next(query string, args []driver.NamedValue) (fakeStep, error) {
+	if len(f.steps) == 0 {
+		f.t.Errorf("unexpected query: %s", query)
+		return fakeStep{}, fmt.Errorf("unexpected query")
+	}
+	step := f.steps[0]
+	f.steps = f.steps[1:]
+	if !strings.Contains(query, step.query) {
+		f.t.Errorf("query %q, want %q", strings.Join(strings.Fields(query), " "), step.query)
+		return fakeStep{}, fmt.Errorf("unexpected query")
+	}
+	if step.args != nil {
+		got := make([]driver.Value, len(args))
+		for i, arg := range args {
+			got[i] = arg.Value
+		}
+		if !reflect.DeepEqual(got, step.args) {
+			f.t.Errorf("query %q: args %v, want %v", step.query, got, step.args)
+		}
+	}
+	return step, step.err
+}
+
+type fakeConn struct{ db *fakeDB }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, fmt.Errorf("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return c, nil }
+
+func (c *fakeConn) Commit() error {
+	c.db.committed = true
+	return nil
+}
+
+func (c *fakeConn) Rollback() error { return nil }
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	step, err := c.db.next(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeRows{columns: step.columns, rows: step.rows}, nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	step, err := c.db.next(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(step.affected), nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"delivery-service/models"
+	"time"
+)
+
+const (
+	// Сериализует обработку попыток по одному ключу между экземплярами сервиса
+	queryLockLoginKey = `
+		SELECT pg_advisory_xact_lock(hashtext('login_attempts'), hashtext($1))`
+
+	queryDeleteOldLoginFailures = `
+		DELETE FROM login_failures WHERE key = $1 AND created_at <= $2`
+
+	queryCreateLoginFailure = `
+		INSERT INTO login_failures (key, created_at) VALUES ($1, $2)`
+
+	queryCountLoginFailures = `
+		SELECT COUNT(*) FROM login_failures WHERE key = $1`
+
+	queryGetLoginLockout = `
+		SELECT lockouts, updated_at FROM login_lockouts WHERE key = $1`
+
+	queryUpsertLoginLockout = `
+		INSERT INTO login_lockouts (key, lockouts, locked_until, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO UPDATE
+		SET lockouts = EXCLUDED.lockouts, locked_until = EXCLUDED.locked_until, updated_at = EXCLUDED.updated_at`
+
+	queryDeleteLoginFailures = `
+		DELETE FROM login_failures WHERE key = $1`
+
+	queryGetLockedUntil = `
+		SELECT locked_until FROM login_lockouts WHERE key = $1`
+
+	queryDeleteLoginLockout = `
+		DELETE FROM login_lockouts WHERE key = $1`
+
+	queryPruneLoginFailures = `
+		DELETE FROM login_failures WHERE created_at <= $1`
+
+	queryPruneLoginLockouts = `
+		DELETE FROM login_lockouts WHERE locked_until <= $1 AND updated_at <= $2`
+)
+
+// LoginAttemptRepository хранит счётчики неудачных входов в PostgreSQL,
+// чтобы ограничения действовали сразу на все экземпляры сервиса
+type LoginAttemptRepository struct {
+	db *sql.DB
+}
+
+func NewLoginAttemptRepository(db *sql.DB) *LoginAttemptRepository {
+	if db == nil {
+		panic("database connection is required")
+	}
+	return &LoginAttemptRepository{db: db}
+}
+
+func (r *LoginAttemptRepository) LockedUntil(ctx context.Context, key string, now time.Time) (time.Time, error) {
+	if key == "" {
+		return time.Time{}, ErrInvalidInput
+	}
+
+	var lockedUntil time.Time
+	ctx, span := startQuery(ctx, "LoginAttemptRepository.LockedUntil", queryGetLockedUntil)
+	err := r.db.QueryRowContext(ctx, queryGetLockedUntil, key).Scan(&lockedUntil)
+	endQuery(span, err)
+	if err == sql.ErrNoRows || (err == nil && !lockedUntil.After(now)) {
+		return time.Time{}, nil
+	}
+	return lockedUntil, err
+}
+
+// RegisterFailure записывает неудачную попытку. Если в окне набралось
+// policy.Limit попыток, ключ блокируется и возвращается время окончания блокировки.
+func (r *LoginAttemptRepository) RegisterFailure(ctx context.Context, key string, now time.Time, policy models.LockoutPolicy) (time.Time, error) {
+	if key == "" {
+		return time.Time{}, ErrInvalidInput
+	}
+
+	// span охватывает всю транзакцию, включая BEGIN и COMMIT
+	ctx, span := startQuery(ctx, "LoginAttemptRepository.RegisterFailure", queryCreateLoginFailure)
+	lockedUntil, err := r.registerFailure(ctx, key, now, policy)
+	endQuery(span, err)
+	return lockedUntil, err
+}
+
+func (r *LoginAttemptRepository) registerFailure(ctx context.Context, key string, now time.Time, policy models.LockoutPolicy) (time.Time, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, queryLockLoginKey, key); err != nil {
+		return time.Time{}, err
+	}
+	if _, err := tx.ExecContext(ctx, queryDeleteOldLoginFailures, key, now.Add(-policy.Window)); err != nil {
+		return time.Time{}, err
+	}
+	if _, err := tx.ExecContext(ctx, queryCreateLoginFailure, key, now); err != nil {
+		return time.Time{}, err
+	}
+
+	var failures int
+	if err := tx.QueryRowContext(ctx, queryCountLoginFailures, key).Scan(&failures); err != nil {
+		return time.Time{}, err
+	}
+	if failures < policy.Limit {
+		return time.Time{}, tx.Commit()
+	}
+
+	lockouts := 0
+	var lastLockout time.Time
+	err = tx.QueryRowContext(ctx, queryGetLoginLockout, key).Scan(&lockouts, &lastLockout)
+	if err != nil && err != sql.ErrNoRows {
+		return time.Time{}, err
+	}
+	if err == sql.ErrNoRows || now.Sub(lastLockout) > policy.ResetAfter {
+		lockouts = 0
+	}
+	lockouts++
+
+	lockedUntil := now.Add(policy.Backoff(lockouts))
+	if _, err := tx.ExecContext(ctx, queryUpsertLoginLockout, key, lockouts, lockedUntil, now); err != nil {
+		return time.Time{}, err
+	}
+	if _, err := tx.ExecContext(ctx, queryDeleteLoginFailures, key); err != nil {
+		return time.Time{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return time.Time{}, err
+	}
+	return lockedUntil, nil
+}
+
+// Reset сбрасывает счётчики ключа после успешного входа
+func (r *LoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	if key == "" {
+		return ErrInvalidInput
+	}
+
+	ctx, span := startQuery(ctx, "LoginAttemptRepository.Reset", queryDeleteLoginFailures)
+	err := r.reset(ctx, key)
+	endQuery(span, err)
+	return err
+}
+
+func (r *LoginAttemptRepository) reset(ctx context.Context, key string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, queryDeleteLoginFailures, key); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, queryDeleteLoginLockout, key); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Prune удаляет старые попытки и истёкшие блокировки всех ключей.
+// RegisterFailure чистит только свой ключ, а ключи разовых email и IP
+// больше не встречаются.
+func (r *LoginAttemptRepository) Prune(ctx context.Context, now time.Time, policy models.LockoutPolicy) error {
+	failuresCtx, span := startQuery(ctx, "LoginAttemptRepository.PruneFailures", queryPruneLoginFailures)
+	_, err := r.db.ExecContext(failuresCtx, queryPruneLoginFailures, now.Add(-policy.Window))
+	endQuery(span, err)
+	if err != nil {
+		return err
+	}
+
+	ctx, span = startQuery(ctx, "LoginAttemptRepository.PruneLockouts", queryPruneLoginLockouts)
+	_, err = r.db.ExecContext(ctx, queryPruneLoginLockouts, now, now.Add(-policy.ResetAfter))
+	endQuery(span, err)
+	return err
+}
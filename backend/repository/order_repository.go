@@ -0,0 +1,511 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"delivery-service/models"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrOrderNotFound      = errors.New("order not found")
+	ErrStatusConflict     = errors.New("order status was changed concurrently")
+	ErrTrackingCodeExists = errors.New("tracking code already exists")
+)
+
+const (
+	queryCreateOrder = `
+		INSERT INTO orders (user_id, tracking_code, status, delivery_address, delivery_date, delivery_time, delivery_notes)
+		VALUES ($1, $2, $3, $4, $5::date, $6, NULLIF($7, ''))
+		RETURNING id, created_at, updated_at`
+
+	queryCreateOrderItem = `
+		INSERT INTO order_items (order_id, marketplace, link, quantity, size, color, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+
+	queryGetOrderByID = `
+		SELECT id, user_id, COALESCE(tracking_code, ''), status, delivery_address, to_char(delivery_date, 'YYYY-MM-DD'),
+			   delivery_time, COALESCE(delivery_notes, ''), created_at, updated_at
+		FROM orders
+		WHERE id = $1 AND user_id = $2`
+
+	queryGetOrdersByUserID = `
+		SELECT id, user_id, COALESCE(tracking_code, ''), status, delivery_address, to_char(delivery_date, 'YYYY-MM-DD'),
+			   delivery_time, COALESCE(delivery_notes, ''), created_at, updated_at
+		FROM orders
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC`
+
+	queryGetOrderByTrackingCode = `
+		SELECT id, user_id, COALESCE(tracking_code, ''), status, delivery_address, to_char(delivery_date, 'YYYY-MM-DD'),
+			   delivery_time, COALESCE(delivery_notes, ''), created_at, updated_at
+		FROM orders
+		WHERE tracking_code = $1`
+
+	queryGetOrderStatus = `
+		SELECT status FROM orders WHERE id = $1`
+
+	queryUpdateOrderStatus = `
+		UPDATE orders SET status = $1, updated_at = NOW()
+		WHERE id = $2 AND status = $3`
+
+	queryCreateStatusEvent = `
+		INSERT INTO order_status_events (order_id, from_status, to_status, actor_id, location, comment)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+
+	queryGetStatusEvents = `
+		SELECT id, order_id, from_status, to_status, actor_id, location, comment, created_at
+		FROM order_status_events
+		WHERE order_id = $1
+		ORDER BY created_at, id`
+
+	queryGetOrderItems = `
+		SELECT id, order_id, marketplace, link, quantity, size, color, notes
+		FROM order_items
+		WHERE order_id = ANY($1)
+		ORDER BY id`
+)
+
+type OrderRepository struct {
+	db *sql.DB
+}
+
+func NewOrderRepository(db *sql.DB) *OrderRepository {
+	if db == nil {
+		panic("database connection is required")
+	}
+	return &OrderRepository{db: db}
+}
+
+func (r *OrderRepository) CreateOrder(ctx context.Context, order *models.Order) error {
+	if order == nil || order.UserID <= 0 || len(order.Items) == 0 {
+		return ErrInvalidInput
+	}
+
+	// span охватывает всю транзакцию, включая BEGIN и COMMIT
+	ctx, span := startQuery(ctx, "OrderRepository.CreateOrder", queryCreateOrder)
+	err := r.createOrder(ctx, order)
+	endQuery(span, err)
+	return err
+}
+
+func (r *OrderRepository) createOrder(ctx context.Context, order *models.Order) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertOrder(ctx, tx, order); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CreateOrderFromCart оформляет заказ из корзины пользователя и очищает её
+// в одной транзакции. Товары заказа берутся из корзины, а не из order.Items.
+func (r *OrderRepository) CreateOrderFromCart(ctx context.Context, order *models.Order) error {
+	if order == nil || order.UserID <= 0 {
+		return ErrInvalidInput
+	}
+
+	ctx, span := startQuery(ctx, "OrderRepository.CreateOrderFromCart", queryCreateOrder)
+	err := r.createOrderFromCart(ctx, order)
+	endQuery(span, err)
+	return err
+}
+
+func (r *OrderRepository) createOrderFromCart(ctx context.Context, order *models.Order) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, queryLockCart, order.UserID); err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, queryGetCartItems, order.UserID)
+	if err != nil {
+		return err
+	}
+	cartItems, err := scanCartItems(rows)
+	rows.Close()
+	if err != nil {
+		return err
+	}
+	if len(cartItems) == 0 {
+		return ErrCartEmpty
+	}
+
+	order.Items = make([]models.OrderItem, 0, len(cartItems))
+	for _, item := range cartItems {
+		order.Items = append(order.Items, models.OrderItem{
+			Marketplace: item.Marketplace,
+			Link:        item.Link,
+			Quantity:    item.Quantity,
+			Size:        item.Size,
+			Color:       item.Color,
+			Notes:       item.Notes,
+		})
+	}
+
+	if err := insertOrder(ctx, tx, order); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, queryClearCart, order.UserID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func insertOrder(ctx context.Context, tx *sql.Tx, order *models.Order) error {
+	err := tx.QueryRowContext(ctx,
+		queryCreateOrder,
+		order.UserID,
+		order.TrackingCode,
+		order.Status,
+		order.DeliveryDetails.Address,
+		order.DeliveryDetails.Date,
+		order.DeliveryDetails.Time,
+		order.DeliveryDetails.Notes,
+	).Scan(&order.ID, &order.CreatedAt, &order.UpdatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" && pqErr.Constraint == "idx_orders_tracking_code" {
+			return ErrTrackingCodeExists
+		}
+		return err
+	}
+
+	event := &models.OrderStatusEvent{
+		OrderID:  order.ID,
+		ToStatus: order.Status,
+		ActorID:  &order.UserID,
+	}
+	if err := createStatusEvent(ctx, tx, event); err != nil {
+		return err
+	}
+	order.History = []models.OrderStatusEvent{*event}
+
+	for i := range order.Items {
+		item := &order.Items[i]
+		item.OrderID = order.ID
+		err = tx.QueryRowContext(ctx,
+			queryCreateOrderItem,
+			item.OrderID,
+			item.Marketplace,
+			item.Link,
+			item.Quantity,
+			item.Size,
+			item.Color,
+			item.Notes,
+		).Scan(&item.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetOrderByID возвращает заказ только если он принадлежит пользователю
+func (r *OrderRepository) GetOrderByID(ctx context.Context, id, userID int64) (*models.Order, error) {
+	if id <= 0 || userID <= 0 {
+		return nil, ErrInvalidInput
+	}
+
+	orderCtx, span := startQuery(ctx, "OrderRepository.GetOrderByID", queryGetOrderByID)
+	order, err := scanOrder(r.db.QueryRowContext(orderCtx, queryGetOrderByID, id, userID))
+	endQuery(span, err)
+	if err == sql.ErrNoRows {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadItems(ctx, []*models.Order{order}); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (r *OrderRepository) GetOrdersByUserID(ctx context.Context, userID int64) ([]*models.Order, error) {
+	if userID <= 0 {
+		return nil, ErrInvalidInput
+	}
+
+	ordersCtx, span := startQuery(ctx, "OrderRepository.GetOrdersByUserID", queryGetOrdersByUserID)
+	orders, err := r.getOrdersByUserID(ordersCtx, userID)
+	endQuery(span, err)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadItems(ctx, orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+func (r *OrderRepository) getOrdersByUserID(ctx context.Context, userID int64) ([]*models.Order, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetOrdersByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make([]*models.Order, 0)
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, rows.Err()
+}
+
+// GetOrderByTrackingCode ищет заказ по трек-номеру без проверки владельца
+func (r *OrderRepository) GetOrderByTrackingCode(ctx context.Context, code string) (*models.Order, error) {
+	if code == "" {
+		return nil, ErrInvalidInput
+	}
+
+	orderCtx, span := startQuery(ctx, "OrderRepository.GetOrderByTrackingCode", queryGetOrderByTrackingCode)
+	order, err := scanOrder(r.db.QueryRowContext(orderCtx, queryGetOrderByTrackingCode, code))
+	endQuery(span, err)
+	if err == sql.ErrNoRows {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadItems(ctx, []*models.Order{order}); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (r *OrderRepository) GetOrderStatus(ctx context.Context, id int64) (string, error) {
+	if id <= 0 {
+		return "", ErrInvalidInput
+	}
+
+	var status string
+	ctx, span := startQuery(ctx, "OrderRepository.GetOrderStatus", queryGetOrderStatus)
+	err := r.db.QueryRowContext(ctx, queryGetOrderStatus, id).Scan(&status)
+	endQuery(span, err)
+	if err == sql.ErrNoRows {
+		return "", ErrOrderNotFound
+	}
+	return status, err
+}
+
+// UpdateStatus переводит заказ в event.ToStatus, только если текущий статус
+// всё ещё равен event.FromStatus, и в той же транзакции сохраняет событие
+func (r *OrderRepository) UpdateStatus(ctx context.Context, event *models.OrderStatusEvent) error {
+	if event == nil || event.OrderID <= 0 || event.FromStatus == nil || event.ToStatus == "" {
+		return ErrInvalidInput
+	}
+
+	ctx, span := startQuery(ctx, "OrderRepository.UpdateStatus", queryUpdateOrderStatus)
+	err := r.updateStatus(ctx, event)
+	endQuery(span, err)
+	return err
+}
+
+func (r *OrderRepository) updateStatus(ctx context.Context, event *models.OrderStatusEvent) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, queryUpdateOrderStatus, event.ToStatus, event.OrderID, *event.FromStatus)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrStatusConflict
+	}
+
+	if err := createStatusEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *OrderRepository) GetStatusEvents(ctx context.Context, orderID int64) ([]models.OrderStatusEvent, error) {
+	if orderID <= 0 {
+		return nil, ErrInvalidInput
+	}
+
+	ctx, span := startQuery(ctx, "OrderRepository.GetStatusEvents", queryGetStatusEvents)
+	events, err := r.getStatusEvents(ctx, orderID)
+	endQuery(span, err)
+	return events, err
+}
+
+func (r *OrderRepository) getStatusEvents(ctx context.Context, orderID int64) ([]models.OrderStatusEvent, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetStatusEvents, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]models.OrderStatusEvent, 0)
+	for rows.Next() {
+		var (
+			event      models.OrderStatusEvent
+			fromStatus sql.NullString
+			actorID    sql.NullInt64
+			location   sql.NullString
+			comment    sql.NullString
+		)
+		if err := rows.Scan(
+			&event.ID,
+			&event.OrderID,
+			&fromStatus,
+			&event.ToStatus,
+			&actorID,
+			&location,
+			&comment,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if fromStatus.Valid {
+			event.FromStatus = &fromStatus.String
+		}
+		if actorID.Valid {
+			event.ActorID = &actorID.Int64
+		}
+		if location.Valid {
+			event.Location = &location.String
+		}
+		if comment.Valid {
+			event.Comment = &comment.String
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanOrder(row rowScanner) (*models.Order, error) {
+	order := &models.Order{}
+	err := row.Scan(
+		&order.ID,
+		&order.UserID,
+		&order.TrackingCode,
+		&order.Status,
+		&order.DeliveryDetails.Address,
+		&order.DeliveryDetails.Date,
+		&order.DeliveryDetails.Time,
+		&order.DeliveryDetails.Notes,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func createStatusEvent(ctx context.Context, tx *sql.Tx, event *models.OrderStatusEvent) error {
+	return tx.QueryRowContext(ctx,
+		queryCreateStatusEvent,
+		event.OrderID,
+		event.FromStatus,
+		event.ToStatus,
+		event.ActorID,
+		event.Location,
+		event.Comment,
+	).Scan(&event.ID, &event.CreatedAt)
+}
+
+// loadItems подгружает товары для всех заказов одним запросом
+func (r *OrderRepository) loadItems(ctx context.Context, orders []*models.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	ctx, span := startQuery(ctx, "OrderRepository.GetOrderItems", queryGetOrderItems)
+	err := r.queryItems(ctx, orders)
+	endQuery(span, err)
+	return err
+}
+
+func (r *OrderRepository) queryItems(ctx context.Context, orders []*models.Order) error {
+	ids := make([]int64, 0, len(orders))
+	byID := make(map[int64]*models.Order, len(orders))
+	for _, order := range orders {
+		order.Items = make([]models.OrderItem, 0)
+		ids = append(ids, order.ID)
+		byID[order.ID] = order
+	}
+
+	rows, err := r.db.QueryContext(ctx, queryGetOrderItems, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			item  models.OrderItem
+			size  sql.NullString
+			color sql.NullString
+			notes sql.NullString
+		)
+		if err := rows.Scan(
+			&item.ID,
+			&item.OrderID,
+			&item.Marketplace,
+			&item.Link,
+			&item.Quantity,
+			&size,
+			&color,
+			&notes,
+		); err != nil {
+			return err
+		}
+		if size.Valid {
+			item.Size = &size.String
+		}
+		if color.Valid {
+			item.Color = &color.String
+		}
+		if notes.Valid {
+			item.Notes = &notes.String
+		}
+
+		if order, ok := byID[item.OrderID]; ok {
+			order.Items = append(order.Items, item)
+		}
+	}
+
+	return rows.Err()
+}
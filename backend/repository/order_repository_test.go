@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"delivery-service/models"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestUpdateOrderStatus(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		steps         []fakeStep
+		wantErr       error
+		wantCommitted bool
+	}{
+		{
+			name: "переход и событие в одной транзакции",
+			steps: []fakeStep{
+				{query: "UPDATE orders SET status", args: []driver.Value{models.OrderStatusAccepted, int64(5), models.OrderStatusCreated}, affected: 1},
+				{
+					query:   "INSERT INTO order_status_events",
+					args:    []driver.Value{int64(5), models.OrderStatusCreated, models.OrderStatusAccepted, int64(3), nil, nil},
+					columns: []string{"id", "created_at"},
+					rows:    [][]driver.Value{{int64(11), now}},
+				},
+			},
+			wantCommitted: true,
+		},
+		{
+			// статус успели изменить: событие не пишется
+			name: "конфликт статуса",
+			steps: []fakeStep{
+				{query: "UPDATE orders SET status", affected: 0},
+			},
+			wantErr: ErrStatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t, tt.steps...)
+			from, actor := models.OrderStatusCreated, int64(3)
+			event := &models.OrderStatusEvent{OrderID: 5, FromStatus: &from, ToStatus: models.OrderStatusAccepted, ActorID: &actor}
+
+			err := NewOrderRepository(db).UpdateStatus(context.Background(), event)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateStatus() = %v, want %v", err, tt.wantErr)
+			}
+			if fake.committed != tt.wantCommitted {
+				t.Errorf("committed = %v, want %v", fake.committed, tt.wantCommitted)
+			}
+			if tt.wantErr == nil && (event.ID != 11 || !event.CreatedAt.Equal(now)) {
+				t.Errorf("event = %+v", event)
+			}
+		})
+	}
+}
+
+func TestUpdateOrderStatusInvalidInput(t *testing.T) {
+	db, _ := newFakeDB(t)
+	repo := NewOrderRepository(db)
+	from := models.OrderStatusCreated
+	for _, event := range []*models.OrderStatusEvent{
+		nil,
+		{OrderID: 5, ToStatus: models.OrderStatusAccepted},
+		{OrderID: 5, FromStatus: &from},
+	} {
+		if err := repo.UpdateStatus(context.Background(), event); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("UpdateStatus(%+v) = %v, want ErrInvalidInput", event, err)
+		}
+	}
+}
+
+// совпадение трек-номера отличается от прочих ошибок: сервис повторит попытку с новым кодом
+func TestCreateOrderTrackingCodeExists(t *testing.T) {
+	tests := []struct {
+		name         string
+		constraint   string
+		wantTracking bool
+	}{
+		{"трек-номер занят", "idx_orders_tracking_code", true},
+		{"другое ограничение", "orders_pkey", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbErr := &pq.Error{Code: "23505", Constraint: tt.constraint}
+			db, fake := newFakeDB(t, fakeStep{query: "INSERT INTO orders", err: dbErr})
+			order := &models.Order{
+				UserID:       3,
+				TrackingCode: "DS0123456789AB",
+				Status:       models.OrderStatusCreated,
+				Items:        []models.OrderItem{{Marketplace: "ozon", Link: "https://ozon.ru/1", Quantity: 1}},
+			}
+
+			err := NewOrderRepository(db).CreateOrder(context.Background(), order)
+			if got := errors.Is(err, ErrTrackingCodeExists); got != tt.wantTracking {
+				t.Errorf("CreateOrder() = %v, tracking code conflict = %v, want %v", err, got, tt.wantTracking)
+			}
+			if !tt.wantTracking && err != dbErr {
+				t.Errorf("CreateOrder() = %v, want the database error", err)
+			}
+			if fake.committed {
+				t.Error("transaction committed")
+			}
+		})
+	}
+}
+
+func TestGetOrderByTrackingCodeNotFound(t *testing.T) {
+	db, _ := newFakeDB(t, fakeStep{query: "WHERE tracking_code = $1", args: []driver.Value{"DS0123456789AB"}, columns: []string{"id"}})
+	if _, err := NewOrderRepository(db).GetOrderByTrackingCode(context.Background(), "DS0123456789AB"); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("GetOrderByTrackingCode() = %v, want ErrOrderNotFound", err)
+	}
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var (
+	ErrResetTokenInvalid = errors.New("reset token invalid, expired or already used")
+)
+
+const (
+	// Новый токен делает недействительными все предыдущие неиспользованные
+	queryInvalidateResetTokens = `
+		UPDATE password_reset_tokens SET used_at = NOW()
+		WHERE user_id = $1 AND used_at IS NULL`
+
+	queryCreateResetToken = `
+		INSERT INTO password_reset_tokens (user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)`
+
+	queryConsumeResetToken = `
+		UPDATE password_reset_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id`
+
+	queryUpdatePasswordHash = `
+		UPDATE users SET password_hash = $1, updated_at = NOW()
+		WHERE id = $2`
+
+	queryRevokeSessionsForReset = `
+		UPDATE sessions SET revoked_at = NOW(), revoke_reason = $1
+		WHERE user_id = $2 AND revoked_at IS NULL`
+)
+
+type PasswordResetRepository struct {
+	db *sql.DB
+}
+
+func NewPasswordResetRepository(db *sql.DB) *PasswordResetRepository {
+	if db == nil {
+		panic("database connection is required")
+	}
+	return &PasswordResetRepository{db: db}
+}
+
+func (r *PasswordResetRepository) CreateToken(ctx context.Context, userID int64, tokenHash string, expiresAt time.Time) error {
+	if userID <= 0 || tokenHash == "" {
+		return ErrInvalidInput
+	}
+
+	// span охватывает всю транзакцию, включая BEGIN и COMMIT
+	ctx, span := startQuery(ctx, "PasswordResetRepository.CreateToken", queryCreateResetToken)
+	err := r.createToken(ctx, userID, tokenHash, expiresAt)
+	endQuery(span, err)
+	return err
+}
+
+func (r *PasswordResetRepository) createToken(ctx context.Context, userID int64, tokenHash string, expiresAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, queryInvalidateResetTokens, userID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, queryCreateResetToken, userID, tokenHash, expiresAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ResetPassword гасит токен, меняет хеш пароля и отзывает все сеансы
+// пользователя в одной транзакции. Возвращает ID пользователя.
+func (r *PasswordResetRepository) ResetPassword(ctx context.Context, tokenHash, passwordHash string) (int64, error) {
+	if tokenHash == "" || passwordHash == "" {
+		return 0, ErrInvalidInput
+	}
+
+	ctx, span := startQuery(ctx, "PasswordResetRepository.ResetPassword", queryConsumeResetToken)
+	userID, err := r.resetPassword(ctx, tokenHash, passwordHash)
+	endQuery(span, err)
+	return userID, err
+}
+
+func (r *PasswordResetRepository) resetPassword(ctx context.Context, tokenHash, passwordHash string) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var userID int64
+	err = tx.QueryRowContext(ctx, queryConsumeResetToken, tokenHash).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return 0, ErrResetTokenInvalid
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, queryUpdatePasswordHash, passwordHash, userID); err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, queryInvalidateResetTokens, userID); err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, queryRevokeSessionsForReset, RevokeReasonPasswordReset, userID); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
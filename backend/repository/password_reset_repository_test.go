@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResetPassword(t *testing.T) {
+	tests := []struct {
+		name          string
+		steps         []fakeStep
+		wantUserID    int64
+		wantErr       error
+		wantCommitted bool
+	}{
+		{
+			name: "пароль изменён",
+			steps: []fakeStep{
+				{query: "WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()", args: []driver.Value{"token"}, columns: []string{"user_id"}, rows: [][]driver.Value{{int64(3)}}},
+				{query: "UPDATE users SET password_hash", args: []driver.Value{"hash", int64(3)}, affected: 1},
+				// остальные токены пользователя тоже гасятся
+				{query: "WHERE user_id = $1 AND used_at IS NULL", args: []driver.Value{int64(3)}},
+				{query: "UPDATE sessions SET revoked_at", args: []driver.Value{RevokeReasonPasswordReset, int64(3)}, affected: 2},
+			},
+			wantUserID:    3,
+			wantCommitted: true,
+		},
+		{
+			// токен не найден, просрочен или уже использован
+			name: "недействительный токен",
+			steps: []fakeStep{
+				{query: "WHERE token_hash = $1", columns: []string{"user_id"}},
+			},
+			wantErr: ErrResetTokenInvalid,
+		},
+		{
+			name: "ошибка при отзыве сеансов",
+			steps: []fakeStep{
+				{query: "WHERE token_hash = $1", columns: []string{"user_id"}, rows: [][]driver.Value{{int64(3)}}},
+				{query: "UPDATE users SET password_hash", affected: 1},
+				{query: "WHERE user_id = $1 AND used_at IS NULL"},
+				{query: "UPDATE sessions SET revoked_at", err: errors.New("connection reset")},
+			},
+			wantErr: errors.New("connection reset"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t, tt.steps...)
+			userID, err := NewPasswordResetRepository(db).ResetPassword(context.Background(), "token", "hash")
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("ResetPassword() error = %v", err)
+			case tt.wantErr != nil && (err == nil || err.Error() != tt.wantErr.Error()):
+				t.Fatalf("ResetPassword() error = %v, want %v", err, tt.wantErr)
+			}
+			if userID != tt.wantUserID {
+				t.Errorf("userID = %d, want %d", userID, tt.wantUserID)
+			}
+			if fake.committed != tt.wantCommitted {
+				t.Errorf("committed = %v, want %v", fake.committed, tt.wantCommitted)
+			}
+		})
+	}
+}
+
+func TestCreateResetToken(t *testing.T) {
+	expiresAt := time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC)
+	db, fake := newFakeDB(t,
+		fakeStep{query: "UPDATE password_reset_tokens SET used_at", args: []driver.Value{int64(3)}, affected: 1},
+		fakeStep{query: "INSERT INTO password_reset_tokens", args: []driver.Value{int64(3), "token", expiresAt}, affected: 1},
+	)
+	repo := NewPasswordResetRepository(db)
+	if err := repo.CreateToken(context.Background(), 3, "token", expiresAt); err != nil {
+		t.Fatal(err)
+	}
+	if !fake.committed {
+		t.Error("transaction was not committed")
+	}
+
+	if err := repo.CreateToken(context.Background(), 3, "", expiresAt); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("empty hash: %v, want ErrInvalidInput", err)
+	}
+}
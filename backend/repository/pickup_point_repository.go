@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"delivery-service/models"
+	"errors"
+)
+
+var (
+	ErrPickupPointNotFound = errors.New("pickup point not found")
+)
+
+const (
+	pickupPointColumns = `
+		id, name, address, city, latitude, longitude, timezone, working_hours,
+		phone, capacity, is_active, created_at, updated_at`
+
+	queryCreatePickupPoint = `
+		INSERT INTO pickup_points (name, address, city, latitude, longitude, timezone,
+								   working_hours, phone, capacity, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at`
+
+	queryUpdatePickupPoint = `
+		UPDATE pickup_points SET
+			name = $1,
+			address = $2,
+			city = $3,
+			latitude = $4,
+			longitude = $5,
+			timezone = $6,
+			working_hours = $7,
+			phone = $8,
+			capacity = $9,
+			is_active = $10,
+			updated_at = NOW()
+		WHERE id = $11
+		RETURNING created_at, updated_at`
+
+	queryDeletePickupPoint = `
+		DELETE FROM pickup_points WHERE id = $1`
+
+	queryGetPickupPointByID = `
+		SELECT ` + pickupPointColumns + `
+		FROM pickup_points
+		WHERE id = $1`
+
+	queryListPickupPoints = `
+		SELECT ` + pickupPointColumns + `
+		FROM pickup_points
+		WHERE is_active OR $1
+		ORDER BY id
+		LIMIT $2`
+
+	// Расстояние по формуле гаверсинусов (радиус Земли 6371 км).
+	// Ограничивающий прямоугольник отсекает заведомо далёкие точки по индексу.
+	queryFindNearbyPickupPoints = `
+		SELECT ` + pickupPointColumns + `, distance
+		FROM (
+			SELECT *, 6371000 * 2 * asin(sqrt(
+				power(sin(radians(latitude - $1) / 2), 2) +
+				cos(radians($1)) * cos(radians(latitude)) *
+				power(sin(radians(longitude - $2) / 2), 2)
+			)) AS distance
+			FROM pickup_points
+			WHERE is_active
+			  AND latitude BETWEEN $3 AND $4
+			  AND longitude BETWEEN $5 AND $6
+		) p
+		WHERE distance <= $7
+		ORDER BY distance
+		LIMIT $8`
+)
+
+type PickupPointRepository struct {
+	db *sql.DB
+}
+
+func NewPickupPointRepository(db *sql.DB) *PickupPointRepository {
+	if db == nil {
+		panic("database connection is required")
+	}
+	return &PickupPointRepository{db: db}
+}
+
+func (r *PickupPointRepository) Create(ctx context.Context, point *models.PickupPoint) error {
+	if point == nil {
+		return ErrInvalidInput
+	}
+
+	ctx, span := startQuery(ctx, "PickupPointRepository.Create", queryCreatePickupPoint)
+	err := r.db.QueryRowContext(ctx,
+		queryCreatePickupPoint,
+		point.Name,
+		point.Address,
+		point.City,
+		point.Latitude,
+		point.Longitude,
+		point.Timezone,
+		point.WorkingHours,
+		point.Phone,
+		point.Capacity,
+		point.IsActive,
+	).Scan(&point.ID, &point.CreatedAt, &point.UpdatedAt)
+	endQuery(span, err)
+	return err
+}
+
+func (r *PickupPointRepository) Update(ctx context.Context, point *models.PickupPoint) error {
+	if point == nil || point.ID <= 0 {
+		return ErrInvalidInput
+	}
+
+	ctx, span := startQuery(ctx, "PickupPointRepository.Update", queryUpdatePickupPoint)
+	err := r.db.QueryRowContext(ctx,
+		queryUpdatePickupPoint,
+		point.Name,
+		point.Address,
+		point.City,
+		point.Latitude,
+		point.Longitude,
+		point.Timezone,
+		point.WorkingHours,
+		point.Phone,
+		point.Capacity,
+		point.IsActive,
+		point.ID,
+	).Scan(&point.CreatedAt, &point.UpdatedAt)
+	endQuery(span, err)
+	if err == sql.ErrNoRows {
+		return ErrPickupPointNotFound
+	}
+	return err
+}
+
+func (r *PickupPointRepository) Delete(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return ErrInvalidInput
+	}
+
+	ctx, span := startQuery(ctx, "PickupPointRepository.Delete", queryDeletePickupPoint)
+	res, err := r.db.ExecContext(ctx, queryDeletePickupPoint, id)
+	endQuery(span, err)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrPickupPointNotFound
+	}
+	return nil
+}
+
+func (r *PickupPointRepository) GetByID(ctx context.Context, id int64) (*models.PickupPoint, error) {
+	if id <= 0 {
+		return nil, ErrInvalidInput
+	}
+
+	point := &models.PickupPoint{}
+	var phone sql.NullString
+	ctx, span := startQuery(ctx, "PickupPointRepository.GetByID", queryGetPickupPointByID)
+	err := r.db.QueryRowContext(ctx, queryGetPickupPointByID, id).Scan(pickupPointFields(point, &phone)...)
+	endQuery(span, err)
+	if err == sql.ErrNoRows {
+		return nil, ErrPickupPointNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if phone.Valid {
+		point.Phone = &phone.String
+	}
+	return point, nil
+}
+
+func (r *PickupPointRepository) List(ctx context.Context, includeInactive bool, limit int) ([]*models.PickupPoint, error) {
+	ctx, span := startQuery(ctx, "PickupPointRepository.List", queryListPickupPoints)
+	points, err := r.list(ctx, includeInactive, limit)
+	endQuery(span, err)
+	return points, err
+}
+
+func (r *PickupPointRepository) list(ctx context.Context, includeInactive bool, limit int) ([]*models.PickupPoint, error) {
+	rows, err := r.db.QueryContext(ctx, queryListPickupPoints, includeInactive, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := make([]*models.PickupPoint, 0)
+	for rows.Next() {
+		point := &models.PickupPoint{}
+		var phone sql.NullString
+		if err := rows.Scan(pickupPointFields(point, &phone)...); err != nil {
+			return nil, err
+		}
+		if phone.Valid {
+			point.Phone = &phone.String
+		}
+		points = append(points, point)
+	}
+
+	return points, rows.Err()
+}
+
+// FindNearby возвращает активные пункты в радиусе radiusMeters, ближайшие первыми.
+// minLat..maxLon - ограничивающий прямоугольник, заранее рассчитанный сервисом.
+func (r *PickupPointRepository) FindNearby(ctx context.Context, lat, lon, radiusMeters, minLat, maxLat, minLon, maxLon float64, limit int) ([]*models.PickupPoint, error) {
+	ctx, span := startQuery(ctx, "PickupPointRepository.FindNearby", queryFindNearbyPickupPoints)
+	points, err := r.findNearby(ctx, lat, lon, radiusMeters, minLat, maxLat, minLon, maxLon, limit)
+	endQuery(span, err)
+	return points, err
+}
+
+func (r *PickupPointRepository) findNearby(ctx context.Context, lat, lon, radiusMeters, minLat, maxLat, minLon, maxLon float64, limit int) ([]*models.PickupPoint, error) {
+	rows, err := r.db.QueryContext(ctx, queryFindNearbyPickupPoints, lat, lon, minLat, maxLat, minLon, maxLon, radiusMeters, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := make([]*models.PickupPoint, 0)
+	for rows.Next() {
+		point := &models.PickupPoint{}
+		var (
+			phone    sql.NullString
+			distance float64
+		)
+		fields := append(pickupPointFields(point, &phone), &distance)
+		if err := rows.Scan(fields...); err != nil {
+			return nil, err
+		}
+		if phone.Valid {
+			point.Phone = &phone.String
+		}
+		point.DistanceMeters = &distance
+		points = append(points, point)
+	}
+
+	return points, rows.Err()
+}
+
+func pickupPointFields(point *models.PickupPoint, phone *sql.NullString) []interface{} {
+	return []interface{}{
+		&point.ID,
+		&point.Name,
+		&point.Address,
+		&point.City,
+		&point.Latitude,
+		&point.Longitude,
+		&point.Timezone,
+		&point.WorkingHours,
+		phone,
+		&point.Capacity,
+		&point.IsActive,
+		&point.CreatedAt,
+		&point.UpdatedAt,
+	}
+}
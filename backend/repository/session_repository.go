@@ -0,0 +1,340 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"delivery-service/models"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrSessionRevoked      = errors.New("session revoked or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token reused")
+	ErrRefreshTokenUnknown = errors.New("refresh token not found")
+)
+
+const (
+	queryCreateSession = `
+		INSERT INTO sessions (user_id, user_agent, ip, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, last_seen_at`
+
+	queryCreateRefreshToken = `
+		INSERT INTO refresh_tokens (session_id, token_hash)
+		VALUES ($1, $2)`
+
+	// Атомарно помечает токен использованным: из двух параллельных
+	// запросов с одним токеном успешным будет только один
+	queryRotateRefreshToken = `
+		UPDATE refresh_tokens SET rotated_at = NOW()
+		WHERE token_hash = $1 AND rotated_at IS NULL
+		RETURNING session_id`
+
+	queryGetRefreshTokenSession = `
+		SELECT session_id FROM refresh_tokens WHERE token_hash = $1`
+
+	// Сеанс заблокированного пользователя не продлевается
+	queryExtendSession = `
+		UPDATE sessions s SET last_seen_at = NOW(), expires_at = $1
+		FROM users u
+		WHERE s.id = $2 AND u.id = s.user_id AND u.blocked_at IS NULL
+			AND s.revoked_at IS NULL AND s.expires_at > NOW()
+		RETURNING s.id, s.user_id, s.user_agent, s.ip, s.created_at, s.last_seen_at, s.expires_at`
+
+	// Отзывает сеанс, если его владелец заблокирован
+	queryRevokeBlockedUserSession = `
+		UPDATE sessions s SET revoked_at = NOW(), revoke_reason = $1
+		FROM users u
+		WHERE s.id = $2 AND u.id = s.user_id AND u.blocked_at IS NOT NULL AND s.revoked_at IS NULL`
+
+	queryIsSessionActive = `
+		SELECT EXISTS (
+			SELECT 1 FROM sessions
+			WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL AND expires_at > NOW()
+		)`
+
+	queryListActiveSessions = `
+		SELECT id, user_id, user_agent, ip, created_at, last_seen_at, expires_at
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY last_seen_at DESC`
+
+	// Пакетное обновление времени последней активности за один запрос
+	queryTouchSessions = `
+		UPDATE sessions s SET last_seen_at = v.seen
+		FROM unnest($1::int[], $2::timestamptz[]) AS v(id, seen)
+		WHERE s.id = v.id AND s.last_seen_at < v.seen`
+
+	queryRevokeSession = `
+		UPDATE sessions SET revoked_at = NOW(), revoke_reason = $1
+		WHERE id = $2 AND revoked_at IS NULL`
+
+	queryRevokeSessionByRefreshToken = `
+		UPDATE sessions SET revoked_at = NOW(), revoke_reason = $1
+		WHERE id = (SELECT session_id FROM refresh_tokens WHERE token_hash = $2) AND revoked_at IS NULL`
+
+	queryRevokeUserSession = `
+		UPDATE sessions SET revoked_at = NOW(), revoke_reason = $1
+		WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL`
+
+	queryRevokeAllUserSessions = `
+		UPDATE sessions SET revoked_at = NOW(), revoke_reason = $1
+		WHERE user_id = $2 AND id <> $3 AND revoked_at IS NULL`
+)
+
+// Причины отзыва сеанса
+const (
+	RevokeReasonLogout        = "logout"
+	RevokeReasonLogoutAll     = "logout_all"
+	RevokeReasonTokenReuse    = "token_reuse"
+	RevokeReasonPassword      = "password_change"
+	RevokeReasonPasswordReset = "password_reset"
+	RevokeReasonBlocked       = "blocked"
+)
+
+type SessionRepository struct {
+	db *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) *SessionRepository {
+	if db == nil {
+		panic("database connection is required")
+	}
+	return &SessionRepository{db: db}
+}
+
+// CreateSession создаёт сеанс вместе с его первым refresh-токеном
+func (r *SessionRepository) CreateSession(ctx context.Context, session *models.Session, tokenHash string) error {
+	if session == nil || session.UserID <= 0 || tokenHash == "" {
+		return ErrInvalidInput
+	}
+
+	// span охватывает всю транзакцию, включая BEGIN и COMMIT
+	ctx, span := startQuery(ctx, "SessionRepository.CreateSession", queryCreateSession)
+	err := r.createSession(ctx, session, tokenHash)
+	endQuery(span, err)
+	return err
+}
+
+func (r *SessionRepository) createSession(ctx context.Context, session *models.Session, tokenHash string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		queryCreateSession,
+		session.UserID,
+		session.UserAgent,
+		session.IP,
+		session.ExpiresAt,
+	).Scan(&session.ID, &session.CreatedAt, &session.LastSeenAt)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, queryCreateRefreshToken, session.ID, tokenHash); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RotateRefreshToken обменивает refresh-токен на новый и продлевает сеанс.
+// Повторное использование уже обменянного токена отзывает весь сеанс,
+// как и обмен токена заблокированным пользователем.
+func (r *SessionRepository) RotateRefreshToken(ctx context.Context, oldHash, newHash string, expiresAt time.Time) (*models.Session, error) {
+	if oldHash == "" || newHash == "" {
+		return nil, ErrInvalidInput
+	}
+
+	ctx, span := startQuery(ctx, "SessionRepository.RotateRefreshToken", queryRotateRefreshToken)
+	session, err := r.rotateRefreshToken(ctx, oldHash, newHash, expiresAt)
+	endQuery(span, err)
+	return session, err
+}
+
+func (r *SessionRepository) rotateRefreshToken(ctx context.Context, oldHash, newHash string, expiresAt time.Time) (*models.Session, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var sessionID int64
+	err = tx.QueryRowContext(ctx, queryRotateRefreshToken, oldHash).Scan(&sessionID)
+	if err == sql.ErrNoRows {
+		err = tx.QueryRowContext(ctx, queryGetRefreshTokenSession, oldHash).Scan(&sessionID)
+		if err == sql.ErrNoRows {
+			return nil, ErrRefreshTokenUnknown
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := tx.ExecContext(ctx, queryRevokeSession, RevokeReasonTokenReuse, sessionID); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.Session{}
+	err = tx.QueryRowContext(ctx, queryExtendSession, expiresAt, sessionID).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.UserAgent,
+		&session.IP,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		// сеанс истёк, отозван или пользователь заблокирован: в последнем
+		// случае сеанс отзывается, чтобы его нельзя было продлить позже
+		if _, err := tx.ExecContext(ctx, queryRevokeBlockedUserSession, RevokeReasonBlocked, sessionID); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		return nil, ErrSessionRevoked
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, queryCreateRefreshToken, session.ID, newHash); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (r *SessionRepository) ListActiveSessions(ctx context.Context, userID int64) ([]*models.Session, error) {
+	if userID <= 0 {
+		return nil, ErrInvalidInput
+	}
+
+	ctx, span := startQuery(ctx, "SessionRepository.ListActiveSessions", queryListActiveSessions)
+	sessions, err := r.listActiveSessions(ctx, userID)
+	endQuery(span, err)
+	return sessions, err
+}
+
+func (r *SessionRepository) listActiveSessions(ctx context.Context, userID int64) ([]*models.Session, error) {
+	rows, err := r.db.QueryContext(ctx, queryListActiveSessions, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]*models.Session, 0)
+	for rows.Next() {
+		session := &models.Session{}
+		if err := rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.UserAgent,
+			&session.IP,
+			&session.CreatedAt,
+			&session.LastSeenAt,
+			&session.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// TouchSessions записывает время последней активности сразу для нескольких сеансов
+func (r *SessionRepository) TouchSessions(ctx context.Context, lastSeen map[int64]time.Time) error {
+	if len(lastSeen) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(lastSeen))
+	times := make([]string, 0, len(lastSeen))
+	for id, seen := range lastSeen {
+		ids = append(ids, id)
+		times = append(times, seen.Format(time.RFC3339Nano))
+	}
+
+	ctx, span := startQuery(ctx, "SessionRepository.TouchSessions", queryTouchSessions)
+	_, err := r.db.ExecContext(ctx, queryTouchSessions, pq.Array(ids), pq.Array(times))
+	endQuery(span, err)
+	return err
+}
+
+func (r *SessionRepository) IsSessionActive(ctx context.Context, sessionID, userID int64) (bool, error) {
+	if sessionID <= 0 || userID <= 0 {
+		return false, ErrInvalidInput
+	}
+
+	var active bool
+	ctx, span := startQuery(ctx, "SessionRepository.IsSessionActive", queryIsSessionActive)
+	err := r.db.QueryRowContext(ctx, queryIsSessionActive, sessionID, userID).Scan(&active)
+	endQuery(span, err)
+	return active, err
+}
+
+// RevokeSession отзывает сеанс пользователя
+func (r *SessionRepository) RevokeSession(ctx context.Context, sessionID, userID int64, reason string) error {
+	if sessionID <= 0 || userID <= 0 {
+		return ErrInvalidInput
+	}
+	return r.execRevoke(ctx, "SessionRepository.RevokeSession", queryRevokeUserSession, reason, sessionID, userID)
+}
+
+// RevokeSessionByRefreshToken отзывает сеанс, которому выдан refresh-токен
+func (r *SessionRepository) RevokeSessionByRefreshToken(ctx context.Context, tokenHash, reason string) error {
+	if tokenHash == "" {
+		return ErrInvalidInput
+	}
+	return r.execRevoke(ctx, "SessionRepository.RevokeSessionByRefreshToken", queryRevokeSessionByRefreshToken, reason, tokenHash)
+}
+
+// execRevoke выполняет отзыв одного сеанса; ErrSessionNotFound, если отзывать нечего
+func (r *SessionRepository) execRevoke(ctx context.Context, name, query string, args ...interface{}) error {
+	ctx, span := startQuery(ctx, name, query)
+	res, err := r.db.ExecContext(ctx, query, args...)
+	endQuery(span, err)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeAllSessions отзывает все сеансы пользователя, кроме exceptID (0 - отозвать все)
+func (r *SessionRepository) RevokeAllSessions(ctx context.Context, userID, exceptID int64, reason string) error {
+	if userID <= 0 {
+		return ErrInvalidInput
+	}
+
+	ctx, span := startQuery(ctx, "SessionRepository.RevokeAllSessions", queryRevokeAllUserSessions)
+	_, err := r.db.ExecContext(ctx, queryRevokeAllUserSessions, reason, userID, exceptID)
+	endQuery(span, err)
+	return err
+}
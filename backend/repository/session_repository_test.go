@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+)
+
+var sessionColumns = []string{"id", "user_id", "user_agent", "ip", "created_at", "last_seen_at", "expires_at"}
+
+func TestRotateRefreshToken(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(30 * 24 * time.Hour)
+	sessionRow := []driver.Value{int64(7), int64(3), "curl", "203.0.113.5", now, now, expiresAt}
+
+	tests := []struct {
+		name          string
+		steps         []fakeStep
+		wantErr       error
+		wantCommitted bool
+	}{
+		{
+			name: "обмен токена",
+			steps: []fakeStep{
+				{query: "UPDATE refresh_tokens SET rotated_at", args: []driver.Value{"old"}, columns: []string{"session_id"}, rows: [][]driver.Value{{int64(7)}}},
+				{query: "UPDATE sessions s SET last_seen_at", args: []driver.Value{expiresAt, int64(7)}, columns: sessionColumns, rows: [][]driver.Value{sessionRow}},
+				{query: "INSERT INTO refresh_tokens", args: []driver.Value{int64(7), "new"}, affected: 1},
+			},
+			wantCommitted: true,
+		},
+		{
+			name: "повторное использование отзывает сеанс",
+			steps: []fakeStep{
+				{query: "UPDATE refresh_tokens SET rotated_at", columns: []string{"session_id"}},
+				{query: "SELECT session_id FROM refresh_tokens", args: []driver.Value{"old"}, columns: []string{"session_id"}, rows: [][]driver.Value{{int64(7)}}},
+				{query: "UPDATE sessions SET revoked_at", args: []driver.Value{RevokeReasonTokenReuse, int64(7)}, affected: 1},
+			},
+			wantErr:       ErrRefreshTokenReused,
+			wantCommitted: true,
+		},
+		{
+			name: "неизвестный токен",
+			steps: []fakeStep{
+				{query: "UPDATE refresh_tokens SET rotated_at", columns: []string{"session_id"}},
+				{query: "SELECT session_id FROM refresh_tokens", columns: []string{"session_id"}},
+			},
+			wantErr: ErrRefreshTokenUnknown,
+		},
+		{
+			// сеанс отозван, истёк или пользователь заблокирован: новый токен не выдаётся
+			name: "сеанс не продлевается",
+			steps: []fakeStep{
+				{query: "UPDATE refresh_tokens SET rotated_at", columns: []string{"session_id"}, rows: [][]driver.Value{{int64(7)}}},
+				{query: "UPDATE sessions s SET last_seen_at", columns: sessionColumns},
+				{query: "u.blocked_at IS NOT NULL", args: []driver.Value{RevokeReasonBlocked, int64(7)}, affected: 1},
+			},
+			wantErr:       ErrSessionRevoked,
+			wantCommitted: true,
+		},
+		{
+			name: "ошибка базы",
+			steps: []fakeStep{
+				{query: "UPDATE refresh_tokens SET rotated_at", err: errors.New("connection reset")},
+			},
+			wantErr: errors.New("connection reset"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t, tt.steps...)
+			repo := NewSessionRepository(db)
+
+			session, err := repo.RotateRefreshToken(context.Background(), "old", "new", expiresAt)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("RotateRefreshToken() error = %v", err)
+			case tt.wantErr != nil && (err == nil || err.Error() != tt.wantErr.Error()):
+				t.Fatalf("RotateRefreshToken() error = %v, want %v", err, tt.wantErr)
+			}
+			if fake.committed != tt.wantCommitted {
+				t.Errorf("committed = %v, want %v", fake.committed, tt.wantCommitted)
+			}
+			if tt.wantErr == nil && (session.ID != 7 || session.UserID != 3 || !session.ExpiresAt.Equal(expiresAt)) {
+				t.Errorf("session = %+v", session)
+			}
+		})
+	}
+}
+
+func TestRotateRefreshTokenInvalidInput(t *testing.T) {
+	db, _ := newFakeDB(t)
+	repo := NewSessionRepository(db)
+	for _, hashes := range [][2]string{{"", "new"}, {"old", ""}} {
+		if _, err := repo.RotateRefreshToken(context.Background(), hashes[0], hashes[1], time.Now()); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("RotateRefreshToken(%q, %q) = %v, want ErrInvalidInput", hashes[0], hashes[1], err)
+		}
+	}
+}
+
+func TestRevokeSessionByRefreshToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{"сеанс отозван", 1, nil},
+		{"сеанс не найден или уже отозван", 0, ErrSessionNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _ := newFakeDB(t, fakeStep{
+				query:    "WHERE id = (SELECT session_id FROM refresh_tokens WHERE token_hash = $2)",
+				args:     []driver.Value{RevokeReasonLogout, "hash"},
+				affected: tt.affected,
+			})
+			err := NewSessionRepository(db).RevokeSessionByRefreshToken(context.Background(), "hash", RevokeReasonLogout)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RevokeSessionByRefreshToken() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	db, _ := newFakeDB(t)
+	if err := NewSessionRepository(db).RevokeSessionByRefreshToken(context.Background(), "", RevokeReasonLogout); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("empty hash: %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestTouchSessions(t *testing.T) {
+	seen := time.Date(2024, 1, 1, 12, 0, 0, 500, time.UTC)
+	db, _ := newFakeDB(t, fakeStep{
+		query:    "FROM unnest($1::int[], $2::timestamptz[])",
+		args:     []driver.Value{"{7}", `{"2024-01-01T12:00:00.0000005Z"}`},
+		affected: 1,
+	})
+	repo := NewSessionRepository(db)
+	if err := repo.TouchSessions(context.Background(), map[int64]time.Time{7: seen}); err != nil {
+		t.Fatal(err)
+	}
+	// пустой пакет не требует запроса
+	if err := repo.TouchSessions(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{"сеанс отозван", 1, nil},
+		// чужой, уже отозванный или несуществующий сеанс
+		{"сеанс не найден", 0, ErrSessionNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _ := newFakeDB(t, fakeStep{
+				query:    "WHERE id = $2 AND user_id = $3",
+				args:     []driver.Value{RevokeReasonLogout, int64(7), int64(3)},
+				affected: tt.affected,
+			})
+			err := NewSessionRepository(db).RevokeSession(context.Background(), 7, 3, RevokeReasonLogout)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RevokeSession() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// текущий сеанс остаётся активным после смены пароля
+func TestRevokeAllSessionsExceptCurrent(t *testing.T) {
+	db, _ := newFakeDB(t, fakeStep{
+		query:    "WHERE user_id = $2 AND id <> $3",
+		args:     []driver.Value{RevokeReasonPassword, int64(3), int64(7)},
+		affected: 2,
+	})
+	if err := NewSessionRepository(db).RevokeAllSessions(context.Background(), 3, 7, RevokeReasonPassword); err != nil {
+		t.Fatal(err)
+	}
+}
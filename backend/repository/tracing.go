@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"delivery-service/tracing"
+	"errors"
+	"strings"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startQuery открывает span одного SQL-запроса. В атрибуты попадает текст
+// запроса с плейсхолдерами, значения параметров не записываются.
+func startQuery(ctx context.Context, name, query string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBQueryText(strings.TrimSpace(query))),
+	)
+}
+
+// endQuery закрывает span запроса; отсутствие строк ошибкой не считается
+func endQuery(span trace.Span, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	tracing.End(span, err)
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// recordSpans подменяет глобальный провайдер на время теста
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestQuerySpan(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus codes.Code
+	}{
+		{"успешный запрос", nil, codes.Unset},
+		// пустой результат - обычный ответ, а не сбой базы
+		{"нет строк", sql.ErrNoRows, codes.Unset},
+		{"нет строк с обёрткой", fmt.Errorf("scan: %w", sql.ErrNoRows), codes.Unset},
+		{"ошибка базы", errors.New("connection reset"), codes.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := recordSpans(t)
+
+			_, span := startQuery(context.Background(), "CartRepository.GetItems", queryGetCartItems)
+			endQuery(span, tt.err)
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("got %d spans, want 1", len(spans))
+			}
+			got := spans[0]
+			if got.Name() != "CartRepository.GetItems" {
+				t.Errorf("name = %q", got.Name())
+			}
+			if got.Status().Code != tt.wantStatus {
+				t.Errorf("status = %v, want %v", got.Status().Code, tt.wantStatus)
+			}
+
+			var text string
+			for _, attr := range got.Attributes() {
+				if attr.Key == semconv.DBQueryTextKey {
+					text = attr.Value.AsString()
+				}
+			}
+			// текст запроса без отступов, значения параметров не записываются
+			if text != "SELECT id, marketplace, link, quantity, size, color, notes, created_at, updated_at\n\t\tFROM cart_items\n\t\tWHERE user_id = $1\n\t\tORDER BY id" {
+				t.Errorf("query text = %q", text)
+			}
+		})
+	}
+}
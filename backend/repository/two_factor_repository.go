@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"delivery-service/models"
+	"errors"
+)
+
+var (
+	ErrTwoFactorNotFound   = errors.New("two-factor secret not found")
+	ErrTwoFactorEnabled    = errors.New("two-factor authentication already enabled")
+	ErrTOTPStepUsed        = errors.New("totp code already used")
+	ErrRecoveryCodeInvalid = errors.New("recovery code invalid or already used")
+)
+
+const (
+	queryGetTOTP = `
+		SELECT user_id, secret, confirmed_at, last_used_step
+		FROM user_totp
+		WHERE user_id = $1`
+
+	// Неподтверждённый секрет можно перевыпустить, включённый - нет
+	querySaveTOTPSecret = `
+		INSERT INTO user_totp (user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, last_used_step = 0, created_at = NOW()
+		WHERE user_totp.confirmed_at IS NULL`
+
+	queryConfirmTOTP = `
+		UPDATE user_totp SET confirmed_at = NOW(), last_used_step = $1
+		WHERE user_id = $2 AND confirmed_at IS NULL AND last_used_step < $1`
+
+	// Условие на last_used_step делает проверку кода атомарной:
+	// один и тот же код нельзя принять дважды
+	queryUseTOTPStep = `
+		UPDATE user_totp SET last_used_step = $1
+		WHERE user_id = $2 AND confirmed_at IS NOT NULL AND last_used_step < $1`
+
+	queryDeleteRecoveryCodes = `
+		DELETE FROM totp_recovery_codes WHERE user_id = $1`
+
+	queryCreateRecoveryCode = `
+		INSERT INTO totp_recovery_codes (user_id, code_hash)
+		VALUES ($1, $2)`
+
+	queryUseRecoveryCode = `
+		UPDATE totp_recovery_codes SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+
+	queryDeleteTOTP = `
+		DELETE FROM user_totp WHERE user_id = $1`
+)
+
+type TwoFactorRepository struct {
+	db *sql.DB
+}
+
+func NewTwoFactorRepository(db *sql.DB) *TwoFactorRepository {
+	if db == nil {
+		panic("database connection is required")
+	}
+	return &TwoFactorRepository{db: db}
+}
+
+func (r *TwoFactorRepository) GetSecret(ctx context.Context, userID int64) (*models.TwoFactorSecret, error) {
+	if userID <= 0 {
+		return nil, ErrInvalidInput
+	}
+
+	secret := &models.TwoFactorSecret{}
+	ctx, span := startQuery(ctx, "TwoFactorRepository.GetSecret", queryGetTOTP)
+	err := r.db.QueryRowContext(ctx, queryGetTOTP, userID).Scan(
+		&secret.UserID,
+		&secret.Secret,
+		&secret.ConfirmedAt,
+		&secret.LastUsedStep,
+	)
+	endQuery(span, err)
+	if err == sql.ErrNoRows {
+		return nil, ErrTwoFactorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+// SaveSecret сохраняет новый секрет, заменяя неподтверждённый
+func (r *TwoFactorRepository) SaveSecret(ctx context.Context, userID int64, secret string) error {
+	if userID <= 0 || secret == "" {
+		return ErrInvalidInput
+	}
+	return r.execOnce(ctx, "TwoFactorRepository.SaveSecret", ErrTwoFactorEnabled, querySaveTOTPSecret, userID, secret)
+}
+
+// Enable включает 2FA и заменяет коды восстановления
+func (r *TwoFactorRepository) Enable(ctx context.Context, userID, step int64, codeHashes []string) error {
+	if userID <= 0 || len(codeHashes) == 0 {
+		return ErrInvalidInput
+	}
+
+	// span охватывает всю транзакцию, включая BEGIN и COMMIT
+	ctx, span := startQuery(ctx, "TwoFactorRepository.Enable", queryConfirmTOTP)
+	err := r.enable(ctx, userID, step, codeHashes)
+	endQuery(span, err)
+	return err
+}
+
+func (r *TwoFactorRepository) enable(ctx context.Context, userID, step int64, codeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, queryConfirmTOTP, step, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrTwoFactorEnabled
+	}
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UseStep отмечает временной шаг TOTP использованным
+func (r *TwoFactorRepository) UseStep(ctx context.Context, userID, step int64) error {
+	if userID <= 0 || step <= 0 {
+		return ErrInvalidInput
+	}
+	return r.execOnce(ctx, "TwoFactorRepository.UseStep", ErrTOTPStepUsed, queryUseTOTPStep, step, userID)
+}
+
+// UseRecoveryCode погашает одноразовый код восстановления
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID int64, codeHash string) error {
+	if userID <= 0 || codeHash == "" {
+		return ErrInvalidInput
+	}
+	return r.execOnce(ctx, "TwoFactorRepository.UseRecoveryCode", ErrRecoveryCodeInvalid, queryUseRecoveryCode, userID, codeHash)
+}
+
+// Disable удаляет секрет и все коды восстановления
+func (r *TwoFactorRepository) Disable(ctx context.Context, userID int64) error {
+	if userID <= 0 {
+		return ErrInvalidInput
+	}
+
+	ctx, span := startQuery(ctx, "TwoFactorRepository.Disable", queryDeleteTOTP)
+	err := r.disable(ctx, userID)
+	endQuery(span, err)
+	return err
+}
+
+func (r *TwoFactorRepository) disable(ctx context.Context, userID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, queryDeleteRecoveryCodes, userID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, queryDeleteTOTP, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// execOnce выполняет изменение, которое должно затронуть ровно одну строку,
+// и возвращает notAffected, если подходящей строки не нашлось
+func (r *TwoFactorRepository) execOnce(ctx context.Context, name string, notAffected error, query string, args ...interface{}) error {
+	ctx, span := startQuery(ctx, name, query)
+	res, err := r.db.ExecContext(ctx, query, args...)
+	endQuery(span, err)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notAffected
+	}
+	return nil
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID int64, codeHashes []string) error {
+	if _, err := tx.ExecContext(ctx, queryDeleteRecoveryCodes, userID); err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, queryCreateRecoveryCode)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, hash := range codeHashes {
+		if _, err := stmt.ExecContext(ctx, userID, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,10 +1,15 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"delivery-service/models"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 var (
@@ -18,20 +23,43 @@ const (
 		VALUES ($1, $2, $3)
 		RETURNING id, created_at, updated_at`
 
+	userColumns = `
+		id, name, email, password_hash, role, COALESCE(avatar, ''), phone, birth_date,
+		address, city, country, postal_code, telegram, whatsapp,
+		preferred_contact, language, notifications, email_verified_at,
+		EXISTS (SELECT 1 FROM user_totp t WHERE t.user_id = users.id AND t.confirmed_at IS NOT NULL),
+		blocked_at, blocked_reason, created_at, updated_at`
+
 	queryGetUserByEmail = `
-		SELECT id, name, email, password_hash, COALESCE(avatar, ''), phone, birth_date,
-			   address, city, country, postal_code, telegram, whatsapp,
-			   preferred_contact, language, notifications, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE email = $1`
 
 	queryGetUserByID = `
-		SELECT id, name, email, password_hash, COALESCE(avatar, ''), phone, birth_date,
-			   address, city, country, postal_code, telegram, whatsapp,
-			   preferred_contact, language, notifications, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE id = $1`
 
+	querySetUserBlocked = `
+		UPDATE users SET blocked_at = $1, blocked_reason = $2, updated_at = NOW()
+		WHERE id = $3`
+
+	querySetUserRole = `
+		UPDATE users SET role = $1, updated_at = NOW()
+		WHERE id = $2`
+
+	queryUpdatePassword = `
+		UPDATE users SET password_hash = $1, updated_at = NOW()
+		WHERE id = $2`
+
+	querySetRoleByEmails = `
+		UPDATE users SET role = $1, updated_at = NOW()
+		WHERE email = ANY($2) AND role <> $1`
+
+	queryMarkEmailVerified = `
+		UPDATE users SET email_verified_at = NOW()
+		WHERE id = $1 AND email = $2 AND email_verified_at IS NULL`
+
 	queryUpdateUser = `
 		UPDATE users SET
 			name = COALESCE($1, name),
@@ -63,78 +91,98 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
-func (r *UserRepository) CreateUser(user *models.User) error {
+func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) error {
 	if user == nil {
 		return ErrInvalidInput
 	}
 
-	return r.db.QueryRow(
+	ctx, span := startQuery(ctx, "UserRepository.CreateUser", queryCreateUser)
+	err := r.db.QueryRowContext(ctx,
 		queryCreateUser,
 		user.Name,
 		user.Email,
 		user.PasswordHash,
 	).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	endQuery(span, err)
+	return err
 }
 
-func (r *UserRepository) GetUserByEmail(email string) (*models.User, error) {
+func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	if email == "" {
 		return nil, ErrInvalidInput
 	}
 
-	user := &models.User{}
-	var (
-		phone            sql.NullString
-		birthDate        sql.NullTime
-		address          sql.NullString
-		city             sql.NullString
-		country          sql.NullString
-		postalCode       sql.NullString
-		telegram         sql.NullString
-		whatsapp         sql.NullString
-		preferredContact sql.NullString
-		language         sql.NullString
-	)
-
-	err := r.db.QueryRow(queryGetUserByEmail, email).Scan(
-		&user.ID,
-		&user.Name,
-		&user.Email,
-		&user.PasswordHash,
-		&user.Avatar,
-		&phone,
-		&birthDate,
-		&address,
-		&city,
-		&country,
-		&postalCode,
-		&telegram,
-		&whatsapp,
-		&preferredContact,
-		&language,
-		&user.Notifications,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
-
+	ctx, span := startQuery(ctx, "UserRepository.GetUserByEmail", queryGetUserByEmail)
+	user, err := scanUser(r.db.QueryRowContext(ctx, queryGetUserByEmail, email))
+	endQuery(span, err)
 	if err == sql.ErrNoRows {
 		return nil, ErrUserNotFound
 	}
-	if err != nil {
-		return nil, err
+	return user, err
+}
+
+// SetRoleByEmails назначает роль пользователям с указанными email
+func (r *UserRepository) SetRoleByEmails(ctx context.Context, emails []string, role string) (int64, error) {
+	if len(emails) == 0 || role == "" {
+		return 0, ErrInvalidInput
 	}
 
-	return r.mapNullableFields(user, phone, birthDate, address, city, country, postalCode, telegram, whatsapp, preferredContact, language), nil
+	ctx, span := startQuery(ctx, "UserRepository.SetRoleByEmails", querySetRoleByEmails)
+	res, err := r.db.ExecContext(ctx, querySetRoleByEmails, role, pq.Array(emails))
+	endQuery(span, err)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
 }
 
-func (r *UserRepository) GetUserByID(id int64) (*models.User, error) {
+func (r *UserRepository) GetUserByID(ctx context.Context, id int64) (*models.User, error) {
 	if id <= 0 {
 		return nil, ErrInvalidInput
 	}
 
+	ctx, span := startQuery(ctx, "UserRepository.GetUserByID", queryGetUserByID)
+	user, err := scanUser(r.db.QueryRowContext(ctx, queryGetUserByID, id))
+	endQuery(span, err)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
+	return user, err
+}
+
+func (r *UserRepository) UpdateUser(ctx context.Context, userID int64, updates *models.UpdateUserRequest) (*models.User, error) {
+	if userID <= 0 || updates == nil {
+		return nil, ErrInvalidInput
+	}
+
+	// пустая дата рождения очищает поле
+	var birthDate *time.Time
+	if updates.BirthDate != nil && *updates.BirthDate != "" {
+		t, err := time.Parse("2006-01-02", *updates.BirthDate)
+		if err != nil {
+			return nil, err
+		}
+		birthDate = &t
+	}
+
+	// span охватывает всю транзакцию, включая BEGIN и COMMIT
+	ctx, span := startQuery(ctx, "UserRepository.UpdateUser", queryUpdateUser)
+	user, err := r.updateUser(ctx, userID, updates, birthDate)
+	endQuery(span, err)
+	return user, err
+}
+
+func (r *UserRepository) updateUser(ctx context.Context, userID int64, updates *models.UpdateUserRequest, birthDate *time.Time) (*models.User, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	user := &models.User{}
 	var (
 		phone            sql.NullString
-		birthDate        sql.NullTime
+		birthDateNull    sql.NullTime
 		address          sql.NullString
 		city             sql.NullString
 		country          sql.NullString
@@ -145,14 +193,26 @@ func (r *UserRepository) GetUserByID(id int64) (*models.User, error) {
 		language         sql.NullString
 	)
 
-	err := r.db.QueryRow(queryGetUserByID, id).Scan(
+	err = tx.QueryRowContext(ctx,
+		queryUpdateUser,
+		updates.Name,
+		updates.Phone,
+		birthDate,
+		updates.Address,
+		updates.City,
+		updates.Country,
+		updates.PostalCode,
+		updates.Telegram,
+		updates.WhatsApp,
+		updates.PreferredContact,
+		updates.Language,
+		userID,
+	).Scan(
 		&user.ID,
 		&user.Name,
 		&user.Email,
-		&user.PasswordHash,
-		&user.Avatar,
 		&phone,
-		&birthDate,
+		&birthDateNull,
 		&address,
 		&city,
 		&country,
@@ -161,45 +221,219 @@ func (r *UserRepository) GetUserByID(id int64) (*models.User, error) {
 		&whatsapp,
 		&preferredContact,
 		&language,
-		&user.Notifications,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
 
-	if err == sql.ErrNoRows {
-		return nil, ErrUserNotFound
-	}
 	if err != nil {
 		return nil, err
 	}
 
-	return r.mapNullableFields(user, phone, birthDate, address, city, country, postalCode, telegram, whatsapp, preferredContact, language), nil
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return mapNullableFields(user, phone, birthDateNull, address, city, country, postalCode, telegram, whatsapp, preferredContact, language), nil
 }
 
-func (r *UserRepository) UpdateUser(userID int64, updates *models.UpdateUserRequest) (*models.User, error) {
-	if userID <= 0 || updates == nil {
-		return nil, ErrInvalidInput
+func (r *UserRepository) UpdatePassword(ctx context.Context, userID int64, passwordHash string) error {
+	if userID <= 0 || passwordHash == "" {
+		return ErrInvalidInput
+	}
+	return r.execUserUpdate(ctx, "UserRepository.UpdatePassword", queryUpdatePassword, passwordHash, userID)
+}
+
+// ListUsers ищет пользователей по фильтру и возвращает страницу и общее число найденных
+func (r *UserRepository) ListUsers(ctx context.Context, filter *models.UserFilter) ([]*models.User, int, error) {
+	if filter == nil || filter.Limit <= 0 || filter.Offset < 0 {
+		return nil, 0, ErrInvalidInput
+	}
+
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	addCondition := func(format string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	if filter.Email != "" {
+		addCondition("email ILIKE $%d", "%"+escapeLike(filter.Email)+"%")
+	}
+	if filter.Name != "" {
+		addCondition("name ILIKE $%d", "%"+escapeLike(filter.Name)+"%")
+	}
+	if filter.City != "" {
+		addCondition("city ILIKE $%d", escapeLike(filter.City))
+	}
+	if filter.Role != "" {
+		addCondition("role = $%d", filter.Role)
+	}
+	if filter.Blocked != nil {
+		addCondition("(blocked_at IS NOT NULL) = $%d", *filter.Blocked)
+	}
+	if filter.CreatedFrom != nil {
+		addCondition("created_at >= $%d", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		addCondition("created_at < $%d", *filter.CreatedTo)
 	}
 
-	tx, err := r.db.Begin()
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	countQuery := "SELECT COUNT(*) FROM users" + where
+	countCtx, span := startQuery(ctx, "UserRepository.CountUsers", countQuery)
+	err := r.db.QueryRowContext(countCtx, countQuery, args...).Scan(&total)
+	endQuery(span, err)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT " + userColumns + " FROM users" + where +
+		fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	ctx, span = startQuery(ctx, "UserRepository.ListUsers", query)
+	users, err := r.listUsers(ctx, query, append(args, filter.Limit, filter.Offset), filter.Limit)
+	endQuery(span, err)
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (r *UserRepository) listUsers(ctx context.Context, query string, args []interface{}, limit int) ([]*models.User, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
-	defer tx.Rollback()
+	defer rows.Close()
 
-	var birthDate *time.Time
-	if updates.BirthDate != nil {
-		t, err := time.Parse("2006-01-02", *updates.BirthDate)
+	users := make([]*models.User, 0, limit)
+	for rows.Next() {
+		user, err := scanUser(rows)
 		if err != nil {
 			return nil, err
 		}
-		birthDate = &t
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// SetBlocked блокирует пользователя (blockedAt != nil) или снимает блокировку
+func (r *UserRepository) SetBlocked(ctx context.Context, userID int64, blockedAt *time.Time, reason *string) error {
+	if userID <= 0 {
+		return ErrInvalidInput
+	}
+	return r.execUserUpdate(ctx, "UserRepository.SetBlocked", querySetUserBlocked, blockedAt, reason, userID)
+}
+
+// Block блокирует пользователя и отзывает все его сеансы в одной транзакции,
+// чтобы заблокированный пользователь не остался с действующими токенами
+func (r *UserRepository) Block(ctx context.Context, userID int64, blockedAt time.Time, reason *string) error {
+	if userID <= 0 {
+		return ErrInvalidInput
+	}
+
+	// span охватывает всю транзакцию, включая BEGIN и COMMIT
+	ctx, span := startQuery(ctx, "UserRepository.Block", querySetUserBlocked)
+	err := r.block(ctx, userID, blockedAt, reason)
+	endQuery(span, err)
+	return err
+}
+
+func (r *UserRepository) block(ctx context.Context, userID int64, blockedAt time.Time, reason *string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, querySetUserBlocked, blockedAt, reason, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrUserNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, queryRevokeAllUserSessions, RevokeReasonBlocked, userID, 0); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *UserRepository) SetRole(ctx context.Context, userID int64, role string) error {
+	if userID <= 0 || role == "" {
+		return ErrInvalidInput
+	}
+	return r.execUserUpdate(ctx, "UserRepository.SetRole", querySetUserRole, role, userID)
+}
+
+func (r *UserRepository) execUserUpdate(ctx context.Context, name, query string, args ...interface{}) error {
+	ctx, span := startQuery(ctx, name, query)
+	res, err := r.db.ExecContext(ctx, query, args...)
+	endQuery(span, err)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE во вводе пользователя
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// MarkEmailVerified подтверждает email, если он не менялся с момента отправки письма.
+// Повторное подтверждение не считается ошибкой.
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, userID int64, email string) error {
+	if userID <= 0 || email == "" {
+		return ErrInvalidInput
+	}
+
+	queryCtx, span := startQuery(ctx, "UserRepository.MarkEmailVerified", queryMarkEmailVerified)
+	res, err := r.db.ExecContext(queryCtx, queryMarkEmailVerified, userID, email)
+	endQuery(span, err)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
 	}
+	if affected > 0 {
+		return nil
+	}
+
+	user, err := r.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Email != email {
+		return ErrUserNotFound
+	}
+	return nil
+}
 
+func scanUser(row rowScanner) (*models.User, error) {
 	user := &models.User{}
 	var (
 		phone            sql.NullString
-		birthDateNull    sql.NullTime
+		birthDate        sql.NullTime
 		address          sql.NullString
 		city             sql.NullString
 		country          sql.NullString
@@ -208,28 +442,18 @@ func (r *UserRepository) UpdateUser(userID int64, updates *models.UpdateUserRequ
 		whatsapp         sql.NullString
 		preferredContact sql.NullString
 		language         sql.NullString
+		blockedReason    sql.NullString
 	)
 
-	err = tx.QueryRow(
-		queryUpdateUser,
-		updates.Name,
-		updates.Phone,
-		birthDate,
-		updates.Address,
-		updates.City,
-		updates.Country,
-		updates.PostalCode,
-		updates.Telegram,
-		updates.WhatsApp,
-		updates.PreferredContact,
-		updates.Language,
-		userID,
-	).Scan(
+	err := row.Scan(
 		&user.ID,
 		&user.Name,
 		&user.Email,
+		&user.PasswordHash,
+		&user.Role,
+		&user.Avatar,
 		&phone,
-		&birthDateNull,
+		&birthDate,
 		&address,
 		&city,
 		&country,
@@ -238,22 +462,25 @@ func (r *UserRepository) UpdateUser(userID int64, updates *models.UpdateUserRequ
 		&whatsapp,
 		&preferredContact,
 		&language,
+		&user.Notifications,
+		&user.EmailVerifiedAt,
+		&user.TwoFactorEnabled,
+		&user.BlockedAt,
+		&blockedReason,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
-
 	if err != nil {
 		return nil, err
 	}
 
-	if err = tx.Commit(); err != nil {
-		return nil, err
+	if blockedReason.Valid {
+		user.BlockedReason = &blockedReason.String
 	}
-
-	return r.mapNullableFields(user, phone, birthDateNull, address, city, country, postalCode, telegram, whatsapp, preferredContact, language), nil
+	return mapNullableFields(user, phone, birthDate, address, city, country, postalCode, telegram, whatsapp, preferredContact, language), nil
 }
 
-func (r *UserRepository) mapNullableFields(
+func mapNullableFields(
 	user *models.User,
 	phone sql.NullString,
 	birthDate sql.NullTime,
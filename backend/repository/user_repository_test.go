@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"delivery-service/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+// userRow - строка users в порядке userColumns
+func userRow(id int64, email string) []driver.Value {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	return []driver.Value{
+		id, "Иван", email, "hash", "user", "",
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		true, now, false, nil, nil, now, now,
+	}
+}
+
+var userRowColumns = []string{
+	"id", "name", "email", "password_hash", "role", "avatar", "phone", "birth_date",
+	"address", "city", "country", "postal_code", "telegram", "whatsapp",
+	"preferred_contact", "language", "notifications", "email_verified_at",
+	"two_factor_enabled", "blocked_at", "blocked_reason", "created_at", "updated_at",
+}
+
+func TestMarkEmailVerified(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   []fakeStep
+		wantErr error
+	}{
+		{
+			name: "email подтверждён",
+			steps: []fakeStep{
+				{query: "WHERE id = $1 AND email = $2 AND email_verified_at IS NULL", args: []driver.Value{int64(3), "user@example.com"}, affected: 1},
+			},
+		},
+		{
+			// повторный переход по ссылке не считается ошибкой
+			name: "уже подтверждён",
+			steps: []fakeStep{
+				{query: "UPDATE users SET email_verified_at", affected: 0},
+				{query: "FROM users", args: []driver.Value{int64(3)}, columns: userRowColumns, rows: [][]driver.Value{userRow(3, "user@example.com")}},
+			},
+		},
+		{
+			// после смены email старая ссылка недействительна
+			name: "email изменён",
+			steps: []fakeStep{
+				{query: "UPDATE users SET email_verified_at", affected: 0},
+				{query: "FROM users", columns: userRowColumns, rows: [][]driver.Value{userRow(3, "new@example.com")}},
+			},
+			wantErr: ErrUserNotFound,
+		},
+		{
+			name: "пользователь удалён",
+			steps: []fakeStep{
+				{query: "UPDATE users SET email_verified_at", affected: 0},
+				{query: "FROM users", columns: userRowColumns},
+			},
+			wantErr: ErrUserNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _ := newFakeDB(t, tt.steps...)
+			err := NewUserRepository(db).MarkEmailVerified(context.Background(), 3, "user@example.com")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("MarkEmailVerified() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListUsersFilter(t *testing.T) {
+	blocked := true
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		filter    models.UserFilter
+		where     string
+		args      []driver.Value
+		listLimit string
+	}{
+		{
+			name:      "без фильтра",
+			filter:    models.UserFilter{Limit: 20},
+			where:     "",
+			args:      []driver.Value{},
+			listLimit: "LIMIT $1 OFFSET $2",
+		},
+		{
+			// спецсимволы LIKE во вводе ищутся буквально
+			name:      "подстрока email",
+			filter:    models.UserFilter{Email: "50%_off", Limit: 20},
+			where:     " WHERE email ILIKE $1",
+			args:      []driver.Value{`%50\%\_off%`},
+			listLimit: "LIMIT $2 OFFSET $3",
+		},
+		{
+			name: "все условия",
+			filter: models.UserFilter{
+				Email: "mail", Name: "Иван", City: "Москва", Role: "admin",
+				Blocked: &blocked, CreatedFrom: &from, CreatedTo: &to,
+				Limit: 20, Offset: 40,
+			},
+			where: " WHERE email ILIKE $1 AND name ILIKE $2 AND city ILIKE $3 AND role = $4" +
+				" AND (blocked_at IS NOT NULL) = $5 AND created_at >= $6 AND created_at < $7",
+			args:      []driver.Value{"%mail%", "%Иван%", "Москва", "admin", true, from, to},
+			listLimit: "LIMIT $8 OFFSET $9",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listArgs := append(append([]driver.Value{}, tt.args...), int64(tt.filter.Limit), int64(tt.filter.Offset))
+			db, _ := newFakeDB(t,
+				fakeStep{query: "SELECT COUNT(*) FROM users" + tt.where, args: tt.args, columns: []string{"count"}, rows: [][]driver.Value{{int64(41)}}},
+				fakeStep{query: "FROM users" + tt.where + " ORDER BY created_at DESC, id DESC " + tt.listLimit, args: listArgs, columns: userRowColumns, rows: [][]driver.Value{userRow(3, "user@example.com")}},
+			)
+
+			users, total, err := NewUserRepository(db).ListUsers(context.Background(), &tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if total != 41 || len(users) != 1 || users[0].ID != 3 {
+				t.Errorf("ListUsers() = %d users, total %d", len(users), total)
+			}
+		})
+	}
+}
+
+func TestListUsersInvalidInput(t *testing.T) {
+	db, _ := newFakeDB(t)
+	repo := NewUserRepository(db)
+	for _, filter := range []*models.UserFilter{nil, {Limit: 0}, {Limit: 20, Offset: -1}} {
+		if _, _, err := repo.ListUsers(context.Background(), filter); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("ListUsers(%+v) = %v, want ErrInvalidInput", filter, err)
+		}
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := map[string]string{
+		"ivan":       "ivan",
+		"100%":       `100\%`,
+		"first_name": `first\_name`,
+		`C:\dir`:     `C:\\dir`,
+	}
+	for input, want := range tests {
+		if got := escapeLike(input); got != want {
+			t.Errorf("escapeLike(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
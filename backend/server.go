@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"delivery-service/config"
+	"delivery-service/handlers"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// serve обслуживает запросы до SIGINT/SIGTERM или ошибки сервера.
+// После сигнала /readyz отвечает 503, через ShutdownDelay сервер перестаёт
+// принимать соединения, а начатые запросы дорабатываются в пределах ShutdownTimeout.
+func serve(server *http.Server, cfg config.ServerConfig, health *handlers.HealthHandler) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if cfg.UseHTTPS {
+			slog.Info("HTTPS-сервер запущен", "port", cfg.Port)
+			serverErr <- server.ListenAndServeTLS(cfg.CertFile, cfg.KeyFile)
+		} else {
+			slog.Info("HTTP-сервер запущен", "port", cfg.Port)
+			serverErr <- server.ListenAndServe()
+		}
+	}()
+
+	select {
+	case err := <-serverErr:
+		return err
+	case <-ctx.Done():
+	}
+	// повторный сигнал завершит процесс сразу
+	stop()
+
+	slog.Info("Получен сигнал остановки, завершаем обработку запросов")
+	health.MarkShuttingDown()
+	time.Sleep(cfg.ShutdownDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		server.Close()
+		return fmt.Errorf("ошибка при завершении обработки запросов: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"delivery-service/models"
+	"delivery-service/repository"
+	"delivery-service/tracing"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidRole      = errors.New("неизвестная роль")
+	ErrCannotModifySelf = errors.New("нельзя заблокировать себя или изменить свою роль")
+	ErrInvalidFilter    = errors.New("некорректные параметры поиска")
+)
+
+const (
+	defaultUsersPerPage = 20
+	maxUsersPerPage     = 100
+	maxBlockReason      = 500
+)
+
+type AdminUserService struct {
+	userRepo  *repository.UserRepository
+	auditRepo *repository.AuditRepository
+}
+
+func NewAdminUserService(userRepo *repository.UserRepository, auditRepo *repository.AuditRepository) *AdminUserService {
+	if userRepo == nil || auditRepo == nil {
+		panic("admin user service dependencies are required")
+	}
+	return &AdminUserService{userRepo: userRepo, auditRepo: auditRepo}
+}
+
+// ListUsers возвращает страницу пользователей. Нумерация страниц с 1.
+func (s *AdminUserService) ListUsers(ctx context.Context, filter *models.UserFilter, page, perPage int) (*models.UserListResponse, error) {
+	ctx, span := tracing.Start(ctx, "AdminUserService.ListUsers")
+	defer span.End()
+
+	if filter == nil {
+		return nil, ErrInvalidFilter
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if perPage <= 0 {
+		perPage = defaultUsersPerPage
+	}
+	if perPage > maxUsersPerPage {
+		perPage = maxUsersPerPage
+	}
+	if filter.Role != "" && !IsValidRole(filter.Role) {
+		return nil, ErrInvalidRole
+	}
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return nil, ErrInvalidFilter
+	}
+
+	filter.Email = strings.TrimSpace(filter.Email)
+	filter.Name = strings.TrimSpace(filter.Name)
+	filter.City = strings.TrimSpace(filter.City)
+	filter.Limit = perPage
+	filter.Offset = (page - 1) * perPage
+
+	users, total, err := s.userRepo.ListUsers(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при поиске пользователей: %w", err)
+	}
+
+	return &models.UserListResponse{
+		Users:   users,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+	}, nil
+}
+
+func (s *AdminUserService) GetUser(ctx context.Context, userID int64) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "AdminUserService.GetUser")
+	defer span.End()
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("ошибка при получении пользователя: %w", err)
+	}
+	return user, nil
+}
+
+// BlockUser блокирует пользователя и завершает все его сеансы
+func (s *AdminUserService) BlockUser(ctx context.Context, actorID, userID int64, req *models.BlockUserRequest, client models.ClientInfo) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "AdminUserService.BlockUser")
+	defer span.End()
+
+	if actorID == userID {
+		return nil, ErrCannotModifySelf
+	}
+	if req == nil {
+		req = &models.BlockUserRequest{}
+	}
+
+	reason := trimOptional(req.Reason)
+	if reason != nil && len([]rune(*reason)) > maxBlockReason {
+		return nil, ErrInvalidInput
+	}
+
+	if err := s.userRepo.Block(ctx, userID, time.Now(), reason); err != nil {
+		return nil, mapAdminUserError(err, "ошибка при блокировке пользователя")
+	}
+
+	details := map[string]interface{}{}
+	if reason != nil {
+		details["reason"] = *reason
+	}
+	recordAdminAudit(ctx, s.auditRepo, actorID, userID, models.AuditUserBlocked, client, details)
+
+	return s.GetUser(ctx, userID)
+}
+
+func (s *AdminUserService) UnblockUser(ctx context.Context, actorID, userID int64, client models.ClientInfo) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "AdminUserService.UnblockUser")
+	defer span.End()
+
+	if err := s.userRepo.SetBlocked(ctx, userID, nil, nil); err != nil {
+		return nil, mapAdminUserError(err, "ошибка при разблокировке пользователя")
+	}
+
+	recordAdminAudit(ctx, s.auditRepo, actorID, userID, models.AuditUserUnblocked, client, nil)
+	return s.GetUser(ctx, userID)
+}
+
+// ChangeRole меняет роль пользователя. Свою роль менять нельзя, чтобы
+// последний администратор случайно не лишил себя доступа.
+func (s *AdminUserService) ChangeRole(ctx context.Context, actorID, userID int64, req *models.ChangeRoleRequest, client models.ClientInfo) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "AdminUserService.ChangeRole")
+	defer span.End()
+
+	if req == nil || !IsValidRole(req.Role) {
+		return nil, ErrInvalidRole
+	}
+	if actorID == userID {
+		return nil, ErrCannotModifySelf
+	}
+
+	user, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == req.Role {
+		return user, nil
+	}
+
+	if err := s.userRepo.SetRole(ctx, userID, req.Role); err != nil {
+		return nil, mapAdminUserError(err, "ошибка при изменении роли")
+	}
+
+	recordAdminAudit(ctx, s.auditRepo, actorID, userID, models.AuditRoleChanged, client, map[string]interface{}{
+		"from": user.Role,
+		"to":   req.Role,
+	})
+
+	user.Role = req.Role
+	return user, nil
+}
+
+func mapAdminUserError(err error, message string) error {
+	if errors.Is(err, repository.ErrUserNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+		return ErrUserNotFound
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
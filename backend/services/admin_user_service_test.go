@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"delivery-service/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+// некорректный фильтр отклоняется до обращения к базе
+func TestListUsersInvalidFilter(t *testing.T) {
+	s := &AdminUserService{}
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	nextDay := day.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name   string
+		filter *models.UserFilter
+		want   error
+	}{
+		{"без фильтра", nil, ErrInvalidFilter},
+		{"неизвестная роль", &models.UserFilter{Role: "root"}, ErrInvalidRole},
+		{"начало позже конца", &models.UserFilter{CreatedFrom: &nextDay, CreatedTo: &day}, ErrInvalidFilter},
+		{"пустой период", &models.UserFilter{CreatedFrom: &day, CreatedTo: &day}, ErrInvalidFilter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.ListUsers(context.Background(), tt.filter, 1, 20); !errors.Is(err, tt.want) {
+				t.Errorf("ListUsers() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+	"delivery-service/logging"
+	"delivery-service/models"
+	"delivery-service/repository"
+)
+
+// recordAudit пишет действие пользователя над своей учётной записью в журнал.
+// Сбой записи не отменяет уже выполненное действие.
+func recordAudit(ctx context.Context, auditRepo *repository.AuditRepository, userID int64, action string, client models.ClientInfo) {
+	entry := &models.AuditEntry{
+		UserID:    &userID,
+		ActorID:   &userID,
+		Action:    action,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+	}
+	if err := auditRepo.Record(ctx, entry); err != nil {
+		logging.FromContext(ctx).Error("Ошибка при записи в журнал аудита", "action", action, "error", err)
+	}
+}
+
+// recordAdminAudit пишет действие сотрудника над чужой учётной записью
+func recordAdminAudit(ctx context.Context, auditRepo *repository.AuditRepository, actorID, userID int64, action string, client models.ClientInfo, details map[string]interface{}) {
+	entry := &models.AuditEntry{
+		UserID:    &userID,
+		ActorID:   &actorID,
+		Action:    action,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		Details:   details,
+	}
+	if err := auditRepo.Record(ctx, entry); err != nil {
+		logging.FromContext(ctx).Error("Ошибка при записи в журнал аудита", "action", action, "error", err)
+	}
+}
@@ -1,14 +1,24 @@
 package services
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"delivery-service/config"
+	"delivery-service/logging"
+	"delivery-service/metrics"
 	"delivery-service/models"
 	"delivery-service/repository"
+	"delivery-service/tracing"
+	"delivery-service/validation"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -20,35 +30,75 @@ var (
 	ErrPasswordMismatch   = errors.New("пароли не совпадают")
 	ErrUserExists         = errors.New("пользователь с таким email уже существует")
 	ErrInvalidToken       = errors.New("недействительный токен")
-	ErrMissingJWTSecret   = errors.New("отсутствует JWT_SECRET в переменных окружения")
 	ErrInvalidEmail       = errors.New("некорректный email")
 	ErrInvalidPassword    = errors.New("пароль должен содержать минимум 8 символов")
-	ErrInvalidName        = errors.New("имя должно содержать минимум 2 символа")
+	ErrInvalidName        = errors.New("имя должно содержать от 2 до 100 символов: буквы, пробелы, дефисы или апострофы")
+	ErrInvalidRefresh     = errors.New("недействительный refresh-токен")
+	ErrSessionNotFound    = errors.New("сеанс не найден")
+	ErrUserBlocked        = errors.New("учётная запись заблокирована, обратитесь в поддержку")
+	ErrInvalidChallenge   = errors.New("время на подтверждение входа истекло, войдите заново")
+	ErrUserNotFound       = errors.New("пользователь не найден")
+	ErrInvalidInput       = errors.New("некорректные входные данные")
 )
 
 const (
-	bcryptCost     = 12
-	tokenExpiresIn = time.Hour * 24 * 7
+	bcryptCost       = 12
+	tokenExpiresIn   = time.Minute * 15
+	refreshExpiresIn = time.Hour * 24 * 30
+	// время на ввод кода второго фактора после проверки пароля
+	challengeExpiresIn = time.Minute * 5
+
+	tokenTypeAccess             = "access"
+	tokenTypeEmailVerification  = "email_verification"
+	tokenTypeTwoFactorChallenge = "two_factor_challenge"
 )
 
 var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 
 type AuthService struct {
-	userRepo *repository.UserRepository
+	userRepo    *repository.UserRepository
+	sessionRepo *repository.SessionRepository
+	twoFactor   *TwoFactorService
+	loginGuard  *LoginGuard
+	jwtSecret   []byte
 }
 
-func NewAuthService(userRepo *repository.UserRepository) *AuthService {
+func NewAuthService(
+	cfg *config.Config,
+	userRepo *repository.UserRepository,
+	sessionRepo *repository.SessionRepository,
+	twoFactor *TwoFactorService,
+	loginGuard *LoginGuard,
+) *AuthService {
 	if userRepo == nil {
 		panic("user repository is required")
 	}
-	return &AuthService{userRepo: userRepo}
+	if sessionRepo == nil {
+		panic("session repository is required")
+	}
+	if twoFactor == nil {
+		panic("two-factor service is required")
+	}
+	if loginGuard == nil {
+		panic("login guard is required")
+	}
+	if cfg.Auth.JWTSecret == "" {
+		panic("jwt secret is required")
+	}
+	return &AuthService{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		twoFactor:   twoFactor,
+		loginGuard:  loginGuard,
+		jwtSecret:   []byte(cfg.Auth.JWTSecret),
+	}
 }
 
-func (s *AuthService) Register(req *models.RegisterRequest) (*models.AuthResponse, error) {
-	log.Printf("Начало регистрации пользователя: %s", req.Email)
+func (s *AuthService) Register(ctx context.Context, req *models.RegisterRequest, client models.ClientInfo) (*models.AuthResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Register")
+	defer span.End()
 
 	if err := s.validateRegistration(req); err != nil {
-		log.Printf("Ошибка валидации при регистрации: %v", err)
 		return nil, err
 	}
 
@@ -56,19 +106,17 @@ func (s *AuthService) Register(req *models.RegisterRequest) (*models.AuthRespons
 	req.Email = strings.TrimSpace(strings.ToLower(req.Email))
 	req.Name = strings.TrimSpace(req.Name)
 
-	existingUser, err := s.userRepo.GetUserByEmail(req.Email)
+	existingUser, err := s.userRepo.GetUserByEmail(ctx, req.Email)
 	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
-		log.Printf("Ошибка при проверке существующего пользователя: %v", err)
 		return nil, fmt.Errorf("ошибка при проверке существующего пользователя: %w", err)
 	}
 	if existingUser != nil {
-		log.Printf("Попытка регистрации с существующим email: %s", req.Email)
+		logging.FromContext(ctx).Info("Попытка регистрации с существующим email", "email", req.Email)
 		return nil, ErrUserExists
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcryptCost)
+	hashedPassword, err := hashPassword(ctx, req.Password)
 	if err != nil {
-		log.Printf("Ошибка при хешировании пароля: %v", err)
 		return nil, fmt.Errorf("ошибка при хешировании пароля: %w", err)
 	}
 
@@ -77,101 +125,263 @@ func (s *AuthService) Register(req *models.RegisterRequest) (*models.AuthRespons
 		Name:          req.Name,
 		Email:         req.Email,
 		PasswordHash:  string(hashedPassword),
+		Role:          models.RoleCustomer,
 		Notifications: true,
 		CreatedAt:     now,
 		UpdatedAt:     now,
 	}
 
-	if err := s.userRepo.CreateUser(user); err != nil {
-		log.Printf("Ошибка при создании пользователя: %v", err)
+	if err := s.userRepo.CreateUser(ctx, user); err != nil {
 		return nil, fmt.Errorf("ошибка при создании пользователя: %w", err)
 	}
 
-	token, err := s.generateToken(user)
-	if err != nil {
-		log.Printf("Ошибка при генерации токена: %v", err)
-		return nil, fmt.Errorf("ошибка при генерации токена: %w", err)
+	return s.startSession(ctx, user, client)
+}
+
+// Login проверяет пароль и открывает сеанс. Если у пользователя включена 2FA,
+// вместо токенов возвращается challenge для POST /api/auth/2fa/verify.
+func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest, client models.ClientInfo) (*models.AuthResponse, *models.TwoFactorChallenge, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Login")
+	defer span.End()
+
+	if err := s.validateLogin(req); err != nil {
+		return nil, nil, err
 	}
 
-	log.Printf("Пользователь успешно зарегистрирован: %s", req.Email)
+	if err := s.loginGuard.Check(ctx, client, req.Email); err != nil {
+		metrics.LoginAttempts.WithLabelValues(metrics.LoginLocked).Inc()
+		return nil, nil, err
+	}
 
-	return &models.AuthResponse{
-		Token: token,
-		User:  user,
-	}, nil
+	user, err := s.userRepo.GetUserByEmail(ctx, strings.TrimSpace(strings.ToLower(req.Email)))
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, nil, fmt.Errorf("ошибка при поиске пользователя: %w", err)
+	}
+
+	// для несуществующего email сравниваем с фиктивным хешем,
+	// чтобы время ответа не выдавало наличие аккаунта
+	passwordHash := dummyPasswordHash()
+	if user != nil {
+		passwordHash = []byte(user.PasswordHash)
+	}
+	if err := comparePassword(ctx, passwordHash, req.Password); err != nil || user == nil {
+		s.loginGuard.Failure(ctx, client, req.Email)
+		metrics.LoginAttempts.WithLabelValues(metrics.LoginFailure).Inc()
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	// о блокировке сообщаем только после проверки пароля,
+	// чтобы не раскрывать состояние чужих учётных записей
+	if user.BlockedAt != nil {
+		metrics.LoginAttempts.WithLabelValues(metrics.LoginBlocked).Inc()
+		return nil, nil, ErrUserBlocked
+	}
+
+	if user.TwoFactorEnabled {
+		challenge, err := s.generateChallenge(user)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ошибка при генерации токена подтверждения: %w", err)
+		}
+		// счётчик сбрасывается только после второго фактора, иначе повторный
+		// ввод пароля позволял бы бесконечно подбирать код
+		metrics.LoginAttempts.WithLabelValues(metrics.LoginTwoFactor).Inc()
+		return nil, challenge, nil
+	}
+
+	s.loginGuard.Success(ctx, req.Email)
+	metrics.LoginAttempts.WithLabelValues(metrics.LoginSuccess).Inc()
+	response, err := s.startSession(ctx, user, client)
+	return response, nil, err
 }
 
-func (s *AuthService) Login(req *models.LoginRequest) (*models.AuthResponse, error) {
-	if err := s.validateLogin(req); err != nil {
+// VerifyTwoFactor завершает вход по challenge-токену и коду второго фактора
+func (s *AuthService) VerifyTwoFactor(ctx context.Context, req *models.TwoFactorVerifyRequest, client models.ClientInfo) (*models.AuthResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.VerifyTwoFactor")
+	defer span.End()
+
+	if req == nil || req.ChallengeToken == "" {
+		return nil, ErrInvalidChallenge
+	}
+
+	userID, err := s.parseChallenge(req.ChallengeToken)
+	if err != nil {
 		return nil, err
 	}
 
-	user, err := s.userRepo.GetUserByEmail(req.Email)
+	user, err := s.userRepo.GetUserByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, repository.ErrUserNotFound) {
-			return nil, ErrInvalidCredentials
+			return nil, ErrInvalidChallenge
+		}
+		return nil, fmt.Errorf("ошибка при получении пользователя: %w", err)
+	}
+	if user.BlockedAt != nil {
+		return nil, ErrUserBlocked
+	}
+
+	// коды второго фактора подбираются так же, как пароли, поэтому
+	// используют тот же счётчик учётной записи
+	if err := s.loginGuard.Check(ctx, client, user.Email); err != nil {
+		metrics.LoginAttempts.WithLabelValues(metrics.LoginLocked).Inc()
+		return nil, err
+	}
+
+	if err := s.twoFactor.VerifyCode(ctx, user.ID, req.Code); err != nil {
+		if errors.Is(err, ErrTwoFactorNotEnabled) {
+			// 2FA отключили, пока пользователь вводил код
+			return nil, ErrInvalidChallenge
 		}
-		return nil, fmt.Errorf("ошибка при поиске пользователя: %w", err)
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			s.loginGuard.Failure(ctx, client, user.Email)
+			metrics.LoginAttempts.WithLabelValues(metrics.LoginFailure).Inc()
+		}
+		return nil, err
 	}
+	s.loginGuard.Success(ctx, user.Email)
+	metrics.LoginAttempts.WithLabelValues(metrics.LoginSuccess).Inc()
+
+	return s.startSession(ctx, user, client)
+}
+
+// Refresh обменивает refresh-токен на новую пару токенов
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*models.AuthResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Refresh")
+	defer span.End()
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
-		return nil, ErrInvalidCredentials
+	if refreshToken == "" {
+		return nil, ErrInvalidRefresh
 	}
 
-	token, err := s.generateToken(user)
+	newToken, newHash, err := generateOpaqueToken()
 	if err != nil {
-		return nil, fmt.Errorf("ошибка при генерации токена: %w", err)
+		return nil, fmt.Errorf("ошибка при генерации refresh-токена: %w", err)
 	}
 
-	return &models.AuthResponse{
-		Token: token,
-		User:  user,
-	}, nil
+	session, err := s.sessionRepo.RotateRefreshToken(ctx, hashToken(refreshToken), newHash, time.Now().Add(refreshExpiresIn))
+	if err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenReused) {
+			logging.FromContext(ctx).Warn("Повторное использование refresh-токена, сеанс отозван")
+			return nil, ErrInvalidRefresh
+		}
+		if errors.Is(err, repository.ErrRefreshTokenUnknown) || errors.Is(err, repository.ErrSessionRevoked) {
+			return nil, ErrInvalidRefresh
+		}
+		return nil, fmt.Errorf("ошибка при обновлении сеанса: %w", err)
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, session.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении пользователя: %w", err)
+	}
+	if user.BlockedAt != nil {
+		// пользователя заблокировали уже после обмена токена
+		if err := s.sessionRepo.RevokeSession(ctx, session.ID, user.ID, repository.RevokeReasonBlocked); err != nil && !errors.Is(err, repository.ErrSessionNotFound) {
+			logging.FromContext(ctx).Error("Ошибка при отзыве сеанса заблокированного пользователя", "error", err)
+		}
+		return nil, ErrInvalidRefresh
+	}
+
+	return s.issueTokens(user, session, newToken)
 }
 
-func (s *AuthService) ValidateToken(tokenString string) (*models.User, error) {
+// Logout завершает текущий сеанс
+func (s *AuthService) Logout(ctx context.Context, userID, sessionID int64) error {
+	_, span := tracing.Start(ctx, "AuthService.Logout")
+	defer span.End()
+
+	if err := s.sessionRepo.RevokeSession(ctx, sessionID, userID, repository.RevokeReasonLogout); err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+			return ErrSessionNotFound
+		}
+		return fmt.Errorf("ошибка при завершении сеанса: %w", err)
+	}
+	return nil
+}
+
+// LogoutByRefreshToken завершает сеанс по его refresh-токену. Токен доступа
+// для этого не нужен, поэтому выход работает и после его истечения.
+func (s *AuthService) LogoutByRefreshToken(ctx context.Context, refreshToken string) error {
+	_, span := tracing.Start(ctx, "AuthService.LogoutByRefreshToken")
+	defer span.End()
+
+	if err := s.sessionRepo.RevokeSessionByRefreshToken(ctx, hashToken(refreshToken), repository.RevokeReasonLogout); err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+			return ErrSessionNotFound
+		}
+		return fmt.Errorf("ошибка при завершении сеанса: %w", err)
+	}
+	return nil
+}
+
+// LogoutAll завершает все сеансы пользователя, включая текущий
+func (s *AuthService) LogoutAll(ctx context.Context, userID int64) error {
+	_, span := tracing.Start(ctx, "AuthService.LogoutAll")
+	defer span.End()
+
+	if err := s.sessionRepo.RevokeAllSessions(ctx, userID, 0, repository.RevokeReasonLogoutAll); err != nil {
+		return fmt.Errorf("ошибка при завершении сеансов: %w", err)
+	}
+	return nil
+}
+
+// ValidateToken проверяет access-токен и возвращает пользователя и ID сеанса.
+// Токены отозванных сеансов отклоняются.
+func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (*models.User, int64, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.ValidateToken")
+	defer span.End()
+
 	if tokenString == "" {
-		return nil, ErrInvalidToken
+		return nil, 0, ErrInvalidToken
 	}
 
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("неожиданный метод подписи: %v", token.Header["alg"])
 		}
-		secret := os.Getenv("JWT_SECRET")
-		if secret == "" {
-			return nil, ErrMissingJWTSecret
-		}
-		return []byte(secret), nil
+		return s.jwtSecret, nil
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("ошибка при проверке токена: %w", err)
+		return nil, 0, fmt.Errorf("ошибка при проверке токена: %w", err)
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		if !s.validateTokenClaims(claims) {
-			return nil, ErrInvalidToken
-		}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid || !s.validateTokenClaims(claims) {
+		return nil, 0, ErrInvalidToken
+	}
 
-		userID := int64(claims["user_id"].(float64))
-		user, err := s.userRepo.GetUserByID(userID)
-		if err != nil {
-			return nil, fmt.Errorf("ошибка при получении пользователя: %w", err)
-		}
+	userID := int64(claims["user_id"].(float64))
+	sessionID := int64(claims["sid"].(float64))
+
+	active, err := s.sessionRepo.IsSessionActive(ctx, sessionID, userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("ошибка при проверке сеанса: %w", err)
+	}
+	if !active {
+		return nil, 0, ErrInvalidToken
+	}
 
-		return user, nil
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("ошибка при получении пользователя: %w", err)
+	}
+	if user.BlockedAt != nil {
+		return nil, 0, ErrUserBlocked
 	}
 
-	return nil, ErrInvalidToken
+	return user, sessionID, nil
 }
 
-func (s *AuthService) UpdateUser(userID int64, req *models.UpdateUserRequest) error {
-	if err := s.validateUpdateRequest(req); err != nil {
+func (s *AuthService) UpdateUser(ctx context.Context, userID int64, req *models.UpdateUserRequest) error {
+	ctx, span := tracing.Start(ctx, "AuthService.UpdateUser")
+	defer span.End()
+
+	normalizeUpdateUserRequest(req)
+	if err := validateUpdateUserRequest(req); err != nil {
 		return err
 	}
 
-	user, err := s.userRepo.UpdateUser(userID, req)
+	user, err := s.userRepo.UpdateUser(ctx, userID, req)
 	if err != nil {
 		return fmt.Errorf("ошибка при обновлении пользователя: %w", err)
 	}
@@ -183,12 +393,15 @@ func (s *AuthService) UpdateUser(userID int64, req *models.UpdateUserRequest) er
 	return nil
 }
 
-func (s *AuthService) GetUserByID(userID int64) (*models.User, error) {
+func (s *AuthService) GetUserByID(ctx context.Context, userID int64) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.GetUserByID")
+	defer span.End()
+
 	if userID <= 0 {
 		return nil, repository.ErrInvalidInput
 	}
 
-	user, err := s.userRepo.GetUserByID(userID)
+	user, err := s.userRepo.GetUserByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка при получении пользователя: %w", err)
 	}
@@ -202,7 +415,7 @@ func (s *AuthService) validateRegistration(req *models.RegisterRequest) error {
 	}
 
 	// Проверка имени
-	if len(strings.TrimSpace(req.Name)) < 2 {
+	if name := strings.TrimSpace(req.Name); name == "" || validation.Name(name) != "" {
 		return ErrInvalidName
 	}
 
@@ -211,12 +424,62 @@ func (s *AuthService) validateRegistration(req *models.RegisterRequest) error {
 		return ErrInvalidEmail
 	}
 
-	// Проверка пароля
-	if len(req.Password) < 8 {
+	return validateNewPassword(req.Password, req.ConfirmPassword)
+}
+
+// normalizeUpdateUserRequest приводит поля профиля к виду, в котором они
+// хранятся: без пробелов по краям, телефоны в E.164, индекс в верхнем регистре
+func normalizeUpdateUserRequest(req *models.UpdateUserRequest) {
+	if req == nil {
+		return
+	}
+	fields := []*string{
+		req.Name, req.Phone, req.BirthDate, req.Address, req.City, req.Country,
+		req.PostalCode, req.Telegram, req.WhatsApp, req.PreferredContact, req.Language,
+	}
+	for _, field := range fields {
+		if field != nil {
+			*field = strings.TrimSpace(*field)
+		}
+	}
+	if req.Phone != nil {
+		*req.Phone = validation.NormalizePhone(*req.Phone)
+	}
+	if req.WhatsApp != nil {
+		*req.WhatsApp = validation.NormalizePhone(*req.WhatsApp)
+	}
+	if req.PostalCode != nil {
+		*req.PostalCode = strings.ToUpper(*req.PostalCode)
+	}
+}
+
+// validateUpdateUserRequest проверяет данные профиля и возвращает ошибки
+// всех полей сразу (validation.Errors)
+func validateUpdateUserRequest(req *models.UpdateUserRequest) error {
+	if req == nil {
+		return ErrInvalidInput
+	}
+
+	return validation.Validate(
+		validation.Field("name", req.Name, validation.NotEmpty, validation.Name),
+		validation.Field("phone", req.Phone, validation.Phone),
+		validation.Field("birth_date", req.BirthDate, validation.BirthDate),
+		validation.Field("postal_code", req.PostalCode, validation.PostalCode(req.Country)),
+		validation.Field("telegram", req.Telegram, validation.Telegram),
+		validation.Field("whatsapp", req.WhatsApp, validation.WhatsApp),
+		validation.Field("preferred_contact", req.PreferredContact, validation.OneOf("invalid_preferred_contact",
+			models.ContactPhone, models.ContactTelegram, models.ContactWhatsApp, models.ContactEmail)),
+		validation.Field("language", req.Language, validation.Language),
+	)
+}
+
+// validateNewPassword - общие правила для нового пароля
+func validateNewPassword(password, confirmPassword string) error {
+	if len(password) < 8 {
 		return ErrInvalidPassword
 	}
 
-	if req.Password != req.ConfirmPassword {
+	if password != confirmPassword {
 		return ErrPasswordMismatch
 	}
 
@@ -230,33 +493,161 @@ func (s *AuthService) validateLogin(req *models.LoginRequest) error {
 	return nil
 }
 
-func (s *AuthService) validateUpdateRequest(req *models.UpdateUserRequest) error {
-	if req == nil {
-		return repository.ErrInvalidInput
-	}
-	// добавить проверки здесь
-	return nil
-}
-
 func (s *AuthService) validateTokenClaims(claims jwt.MapClaims) bool {
 	exp, ok := claims["exp"].(float64)
 	if !ok {
 		return false
 	}
+	if _, ok := claims["user_id"].(float64); !ok {
+		return false
+	}
+	// токены, выпущенные до появления сеансов, не содержат sid
+	if _, ok := claims["sid"].(float64); !ok {
+		return false
+	}
+	// токены другого назначения (например, подтверждение email) не дают доступа к API
+	if typ, _ := claims["typ"].(string); typ != tokenTypeAccess {
+		return false
+	}
 	return time.Now().Unix() < int64(exp)
 }
 
-func (s *AuthService) generateToken(user *models.User) (string, error) {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		return "", ErrMissingJWTSecret
+// startSession создаёт новый сеанс и выдаёт для него пару токенов
+func (s *AuthService) startSession(ctx context.Context, user *models.User, client models.ClientInfo) (*models.AuthResponse, error) {
+	refreshToken, refreshHash, err := generateOpaqueToken()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при генерации refresh-токена: %w", err)
 	}
 
+	session := &models.Session{
+		UserID:    user.ID,
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
+		ExpiresAt: time.Now().Add(refreshExpiresIn),
+	}
+	if err := s.sessionRepo.CreateSession(ctx, session, refreshHash); err != nil {
+		return nil, fmt.Errorf("ошибка при создании сеанса: %w", err)
+	}
+
+	return s.issueTokens(user, session, refreshToken)
+}
+
+func (s *AuthService) issueTokens(user *models.User, session *models.Session, refreshToken string) (*models.AuthResponse, error) {
+	expiresAt := time.Now().Add(tokenExpiresIn)
+	token, err := s.generateToken(user, session.ID, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при генерации токена: %w", err)
+	}
+
+	return &models.AuthResponse{
+		Token:            token,
+		TokenExpiresAt:   expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
+		User:             user,
+	}, nil
+}
+
+func (s *AuthService) generateToken(user *models.User, sessionID int64, expiresAt time.Time) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"user_id": user.ID,
 		"email":   user.Email,
-		"exp":     time.Now().Add(tokenExpiresIn).Unix(),
+		"role":    user.Role,
+		"sid":     sessionID,
+		"typ":     tokenTypeAccess,
+		"exp":     expiresAt.Unix(),
+	})
+
+	return token.SignedString(s.jwtSecret)
+}
+
+func (s *AuthService) generateChallenge(user *models.User) (*models.TwoFactorChallenge, error) {
+	expiresAt := time.Now().Add(challengeExpiresIn)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": user.ID,
+		"typ":     tokenTypeTwoFactorChallenge,
+		"exp":     expiresAt.Unix(),
+	})
+
+	signed, err := token.SignedString(s.jwtSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TwoFactorChallenge{
+		TwoFactorRequired: true,
+		ChallengeToken:    signed,
+		ExpiresAt:         expiresAt,
+	}, nil
+}
+
+func (s *AuthService) parseChallenge(tokenString string) (int64, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("неожиданный метод подписи: %v", token.Header["alg"])
+		}
+		return s.jwtSecret, nil
 	})
+	if err != nil || !token.Valid {
+		return 0, ErrInvalidChallenge
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return 0, ErrInvalidChallenge
+	}
+	typ, _ := claims["typ"].(string)
+	userID, okID := claims["user_id"].(float64)
+	if typ != tokenTypeTwoFactorChallenge || !okID {
+		return 0, ErrInvalidChallenge
+	}
+
+	return int64(userID), nil
+}
+
+// hashPassword и comparePassword - обёртки над bcrypt с замером времени
+func hashPassword(ctx context.Context, password string) ([]byte, error) {
+	_, span := tracing.Start(ctx, "bcrypt.GenerateFromPassword")
+	defer span.End()
+	defer metrics.ObservePasswordHash("hash", time.Now())
+	return bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
+}
+
+func comparePassword(ctx context.Context, hash []byte, password string) error {
+	_, span := tracing.Start(ctx, "bcrypt.CompareHashAndPassword")
+	defer span.End()
+	defer metrics.ObservePasswordHash("compare", time.Now())
+	return bcrypt.CompareHashAndPassword(hash, []byte(password))
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+)
+
+// dummyPasswordHash - bcrypt-хеш той же стоимости, что и настоящие
+func dummyPasswordHash() []byte {
+	dummyHashOnce.Do(func() {
+		hash, err := bcrypt.GenerateFromPassword([]byte("dummy-password-for-timing"), bcryptCost)
+		if err != nil {
+			slog.Error("Ошибка при генерации фиктивного хеша", "error", err)
+		}
+		dummyHash = hash
+	})
+	return dummyHash
+}
+
+// generateOpaqueToken возвращает случайный токен и его SHA-256 хеш для хранения в БД
+func generateOpaqueToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashToken(token), nil
+}
 
-	return token.SignedString([]byte(secret))
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
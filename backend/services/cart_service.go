@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"delivery-service/models"
+	"delivery-service/repository"
+	"delivery-service/tracing"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrCartItemNotFound = errors.New("товар в корзине не найден")
+	ErrCartFull         = errors.New("в корзине не может быть больше 50 позиций")
+	ErrCartEmpty        = errors.New("корзина пуста")
+)
+
+type CartService struct {
+	cartRepo *repository.CartRepository
+}
+
+func NewCartService(cartRepo *repository.CartRepository) *CartService {
+	if cartRepo == nil {
+		panic("cart repository is required")
+	}
+	return &CartService{cartRepo: cartRepo}
+}
+
+func (s *CartService) GetCart(ctx context.Context, userID int64) (*models.Cart, error) {
+	ctx, span := tracing.Start(ctx, "CartService.GetCart")
+	defer span.End()
+
+	if userID <= 0 {
+		return nil, ErrInvalidInput
+	}
+
+	items, err := s.cartRepo.GetItems(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении корзины: %w", err)
+	}
+
+	cart := &models.Cart{Items: items}
+	marketplaces := make(map[string]bool)
+	for _, item := range items {
+		cart.TotalItems += item.Quantity
+		marketplaces[item.Marketplace] = true
+	}
+	cart.UniqueMarketplaces = len(marketplaces)
+
+	return cart, nil
+}
+
+func (s *CartService) AddItem(ctx context.Context, userID int64, req *models.OrderItemRequest) (*models.CartItem, error) {
+	ctx, span := tracing.Start(ctx, "CartService.AddItem")
+	defer span.End()
+
+	if userID <= 0 || req == nil {
+		return nil, ErrInvalidInput
+	}
+
+	if err := validateOrderItem(req); err != nil {
+		return nil, err
+	}
+
+	item := newCartItem(req)
+	if err := s.cartRepo.AddItem(ctx, userID, item, maxOrderItems); err != nil {
+		return nil, mapCartError(err, "ошибка при добавлении товара в корзину")
+	}
+
+	return item, nil
+}
+
+func (s *CartService) UpdateItem(ctx context.Context, userID, itemID int64, req *models.UpdateCartItemRequest) (*models.CartItem, error) {
+	ctx, span := tracing.Start(ctx, "CartService.UpdateItem")
+	defer span.End()
+
+	if userID <= 0 || itemID <= 0 || req == nil {
+		return nil, ErrInvalidInput
+	}
+
+	if req.Quantity != nil && *req.Quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	update := &models.UpdateCartItemRequest{
+		Quantity: req.Quantity,
+		Size:     trimOptional(req.Size),
+		Color:    trimOptional(req.Color),
+		Notes:    trimOptional(req.Notes),
+	}
+
+	item, err := s.cartRepo.UpdateItem(ctx, userID, itemID, update)
+	if err != nil {
+		return nil, mapCartError(err, "ошибка при обновлении товара в корзине")
+	}
+
+	return item, nil
+}
+
+func (s *CartService) RemoveItem(ctx context.Context, userID, itemID int64) error {
+	ctx, span := tracing.Start(ctx, "CartService.RemoveItem")
+	defer span.End()
+
+	if userID <= 0 || itemID <= 0 {
+		return ErrInvalidInput
+	}
+
+	if err := s.cartRepo.DeleteItem(ctx, userID, itemID); err != nil {
+		return mapCartError(err, "ошибка при удалении товара из корзины")
+	}
+
+	return nil
+}
+
+// ReplaceCart заменяет корзину целиком, пустой список очищает её
+func (s *CartService) ReplaceCart(ctx context.Context, userID int64, req *models.CartItemsRequest) (*models.Cart, error) {
+	ctx, span := tracing.Start(ctx, "CartService.ReplaceCart")
+	defer span.End()
+
+	items, err := s.prepareItems(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cartRepo.ReplaceItems(ctx, userID, items, maxOrderItems); err != nil {
+		return nil, mapCartError(err, "ошибка при сохранении корзины")
+	}
+
+	return s.GetCart(ctx, userID)
+}
+
+// MergeCart объединяет анонимную корзину, собранную до входа, с корзиной пользователя
+func (s *CartService) MergeCart(ctx context.Context, userID int64, req *models.CartItemsRequest) (*models.Cart, error) {
+	ctx, span := tracing.Start(ctx, "CartService.MergeCart")
+	defer span.End()
+
+	items, err := s.prepareItems(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) > 0 {
+		if err := s.cartRepo.MergeItems(ctx, userID, items, maxOrderItems); err != nil {
+			return nil, mapCartError(err, "ошибка при объединении корзин")
+		}
+	}
+
+	return s.GetCart(ctx, userID)
+}
+
+func (s *CartService) prepareItems(userID int64, req *models.CartItemsRequest) ([]models.CartItem, error) {
+	if userID <= 0 || req == nil {
+		return nil, ErrInvalidInput
+	}
+	if len(req.Items) > maxOrderItems {
+		return nil, ErrCartFull
+	}
+
+	items := make([]models.CartItem, 0, len(req.Items))
+	for i := range req.Items {
+		if err := validateOrderItem(&req.Items[i]); err != nil {
+			return nil, err
+		}
+		items = append(items, *newCartItem(&req.Items[i]))
+	}
+
+	return items, nil
+}
+
+func newCartItem(req *models.OrderItemRequest) *models.CartItem {
+	return &models.CartItem{
+		Marketplace: strings.TrimSpace(req.Marketplace),
+		Link:        strings.TrimSpace(req.Link),
+		Quantity:    req.Quantity,
+		Size:        trimOptional(req.Size),
+		Color:       trimOptional(req.Color),
+		Notes:       trimOptional(req.Notes),
+	}
+}
+
+func mapCartError(err error, message string) error {
+	switch {
+	case errors.Is(err, repository.ErrCartItemNotFound):
+		return ErrCartItemNotFound
+	case errors.Is(err, repository.ErrCartFull):
+		return ErrCartFull
+	default:
+		return fmt.Errorf("%s: %w", message, err)
+	}
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"delivery-service/models"
+	"delivery-service/repository"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestPrepareCartItems(t *testing.T) {
+	str := func(s string) *string { return &s }
+	s := &CartService{}
+
+	items, err := s.prepareItems(1, &models.CartItemsRequest{Items: []models.OrderItemRequest{
+		{Marketplace: " ozon ", Link: " https://ozon.ru/1 ", Quantity: 2, Size: str(" M "), Color: str("  ")},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := items[0]
+	if item.Marketplace != "ozon" || item.Link != "https://ozon.ru/1" || *item.Size != "M" || item.Color != nil {
+		t.Errorf("item = %+v, want trimmed fields and empty color dropped", item)
+	}
+
+	tooMany := make([]models.OrderItemRequest, maxOrderItems+1)
+	tests := []struct {
+		name    string
+		userID  int64
+		req     *models.CartItemsRequest
+		wantErr error
+	}{
+		{"без пользователя", 0, &models.CartItemsRequest{}, ErrInvalidInput},
+		{"без запроса", 1, nil, ErrInvalidInput},
+		{"слишком много позиций", 1, &models.CartItemsRequest{Items: tooMany}, ErrCartFull},
+		{"ссылка не http", 1, &models.CartItemsRequest{Items: []models.OrderItemRequest{{Marketplace: "ozon", Link: "ftp://ozon.ru/1", Quantity: 1}}}, ErrInvalidOrderItem},
+		{"нулевое количество", 1, &models.CartItemsRequest{Items: []models.OrderItemRequest{{Marketplace: "ozon", Link: "https://ozon.ru/1"}}}, ErrInvalidQuantity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.prepareItems(tt.userID, tt.req); !errors.Is(err, tt.wantErr) {
+				t.Errorf("prepareItems() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMapCartError(t *testing.T) {
+	dbErr := errors.New("connection reset")
+	tests := []struct {
+		err, want error
+	}{
+		{repository.ErrCartItemNotFound, ErrCartItemNotFound},
+		{fmt.Errorf("insert: %w", repository.ErrCartFull), ErrCartFull},
+		{dbErr, dbErr},
+	}
+	for _, tt := range tests {
+		if got := mapCartError(tt.err, "ошибка"); !errors.Is(got, tt.want) {
+			t.Errorf("mapCartError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
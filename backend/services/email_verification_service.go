@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"delivery-service/config"
+	"delivery-service/i18n"
+	"delivery-service/mailer"
+	"delivery-service/models"
+	"delivery-service/repository"
+	"delivery-service/tracing"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidVerificationToken = errors.New("ссылка для подтверждения email недействительна или устарела")
+	ErrEmailAlreadyVerified     = errors.New("email уже подтверждён")
+)
+
+const verificationTokenExpiresIn = time.Hour * 48
+
+type EmailVerificationService struct {
+	userRepo  *repository.UserRepository
+	mailer    *mailer.AsyncMailer
+	appURL    string
+	jwtSecret []byte
+}
+
+func NewEmailVerificationService(cfg *config.Config, userRepo *repository.UserRepository, m *mailer.AsyncMailer) *EmailVerificationService {
+	if userRepo == nil || m == nil {
+		panic("user repository and mailer are required")
+	}
+	if cfg.Auth.JWTSecret == "" {
+		panic("jwt secret is required")
+	}
+
+	return &EmailVerificationService{
+		userRepo:  userRepo,
+		mailer:    m,
+		appURL:    cfg.Server.AppURL,
+		jwtSecret: []byte(cfg.Auth.JWTSecret),
+	}
+}
+
+// SendVerification отправляет письмо со ссылкой для подтверждения email
+// на языке пользователя, а если он не выбран - на языке запроса
+func (s *EmailVerificationService) SendVerification(ctx context.Context, user *models.User) error {
+	if user == nil {
+		return ErrInvalidInput
+	}
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+
+	token, err := s.generateToken(user)
+	if err != nil {
+		return fmt.Errorf("ошибка при генерации ссылки подтверждения: %w", err)
+	}
+
+	link := s.appURL + "/verify-email?token=" + url.QueryEscape(token)
+	lang := i18n.Preferred(user.Language, i18n.FromContext(ctx))
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: i18n.T(lang, "email.verify.subject"),
+		Body:    i18n.T(lang, "email.greeting", "name", user.Name) + "\n\n" + i18n.T(lang, "email.verify.body", "link", link),
+	}
+
+	if err := s.mailer.Send(msg); err != nil {
+		return fmt.Errorf("ошибка при отправке письма подтверждения: %w", err)
+	}
+
+	return nil
+}
+
+// Resend повторно отправляет письмо текущему пользователю
+func (s *EmailVerificationService) Resend(ctx context.Context, userID int64) error {
+	ctx, span := tracing.Start(ctx, "EmailVerificationService.Resend")
+	defer span.End()
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("ошибка при получении пользователя: %w", err)
+	}
+	return s.SendVerification(ctx, user)
+}
+
+// Verify подтверждает email по подписанной ссылке. Токен привязан к адресу,
+// поэтому после смены email старые ссылки перестают работать.
+func (s *EmailVerificationService) Verify(ctx context.Context, tokenString string) error {
+	ctx, span := tracing.Start(ctx, "EmailVerificationService.Verify")
+	defer span.End()
+
+	if tokenString == "" {
+		return ErrInvalidVerificationToken
+	}
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("неожиданный метод подписи: %v", token.Header["alg"])
+		}
+		return s.jwtSecret, nil
+	})
+	if err != nil || !token.Valid {
+		return ErrInvalidVerificationToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return ErrInvalidVerificationToken
+	}
+	typ, _ := claims["typ"].(string)
+	userID, okID := claims["user_id"].(float64)
+	email, okEmail := claims["email"].(string)
+	if typ != tokenTypeEmailVerification || !okID || !okEmail {
+		return ErrInvalidVerificationToken
+	}
+
+	if err := s.userRepo.MarkEmailVerified(ctx, int64(userID), email); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+			return ErrInvalidVerificationToken
+		}
+		return fmt.Errorf("ошибка при подтверждении email: %w", err)
+	}
+
+	return nil
+}
+
+func (s *EmailVerificationService) generateToken(user *models.User) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"typ":     tokenTypeEmailVerification,
+		"user_id": user.ID,
+		"email":   user.Email,
+		"exp":     time.Now().Add(verificationTokenExpiresIn).Unix(),
+	})
+
+	return token.SignedString(s.jwtSecret)
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"delivery-service/models"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestEmailVerificationRejectsInvalidTokens(t *testing.T) {
+	secret := []byte("secret")
+	// до обращения к базе проверка не доходит, репозиторий не нужен
+	s := &EmailVerificationService{jwtSecret: secret}
+	sign := func(key []byte, claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	exp := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"пустой токен", ""},
+		{"мусор", "not-a-token"},
+		{"чужая подпись", sign([]byte("other"), jwt.MapClaims{"typ": tokenTypeEmailVerification, "user_id": 3, "email": "user@example.com", "exp": exp})},
+		{"ссылка устарела", sign(secret, jwt.MapClaims{"typ": tokenTypeEmailVerification, "user_id": 3, "email": "user@example.com", "exp": time.Now().Add(-time.Minute).Unix()})},
+		// access-токен той же подписью не подтверждает email
+		{"другой тип токена", sign(secret, jwt.MapClaims{"typ": tokenTypeAccess, "user_id": 3, "email": "user@example.com", "exp": exp})},
+		{"без email", sign(secret, jwt.MapClaims{"typ": tokenTypeEmailVerification, "user_id": 3, "exp": exp})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.Verify(context.Background(), tt.token); !errors.Is(err, ErrInvalidVerificationToken) {
+				t.Errorf("Verify() = %v, want ErrInvalidVerificationToken", err)
+			}
+		})
+	}
+}
+
+func TestGenerateVerificationToken(t *testing.T) {
+	s := &EmailVerificationService{jwtSecret: []byte("secret")}
+	token, err := s.generateToken(&models.User{ID: 3, Email: "user@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) { return s.jwtSecret, nil }); err != nil {
+		t.Fatal(err)
+	}
+	if claims["typ"] != tokenTypeEmailVerification || claims["user_id"] != float64(3) || claims["email"] != "user@example.com" {
+		t.Errorf("claims = %v", claims)
+	}
+}
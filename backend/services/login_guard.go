@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"delivery-service/logging"
+	"delivery-service/models"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrTooManyLoginAttempts = errors.New("слишком много неудачных попыток входа, попробуйте позже")
+
+// LoginLockedError возвращается, пока действует временная блокировка входа
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return ErrTooManyLoginAttempts.Error()
+}
+
+func (e *LoginLockedError) Unwrap() error {
+	return ErrTooManyLoginAttempts
+}
+
+// LoginAttemptStore хранит неудачные попытки и блокировки. Для одного
+// экземпляра подходит MemoryLoginAttemptStore, для нескольких -
+// repository.LoginAttemptRepository.
+type LoginAttemptStore interface {
+	// LockedUntil возвращает окончание блокировки или нулевое время, если её нет
+	LockedUntil(ctx context.Context, key string, now time.Time) (time.Time, error)
+	// RegisterFailure учитывает неудачную попытку и, если лимит исчерпан,
+	// блокирует ключ, возвращая окончание блокировки
+	RegisterFailure(ctx context.Context, key string, now time.Time, policy models.LockoutPolicy) (time.Time, error)
+	Reset(ctx context.Context, key string) error
+	// Prune удаляет попытки старше policy.Window и блокировки, которые
+	// истекли и старше policy.ResetAfter, по всем ключам
+	Prune(ctx context.Context, now time.Time, policy models.LockoutPolicy) error
+}
+
+var (
+	// по IP лимит выше: за одним адресом может быть много пользователей (NAT, офис)
+	ipLockoutPolicy = models.LockoutPolicy{
+		Limit:       20,
+		Window:      15 * time.Minute,
+		BaseLockout: 5 * time.Minute,
+		MaxLockout:  time.Hour,
+		ResetAfter:  24 * time.Hour,
+	}
+	accountLockoutPolicy = models.LockoutPolicy{
+		Limit:       5,
+		Window:      15 * time.Minute,
+		BaseLockout: time.Minute,
+		MaxLockout:  time.Hour,
+		ResetAfter:  24 * time.Hour,
+	}
+
+	// записи, которые уже не нужны ни одной из политик
+	pruneLockoutPolicy = models.LockoutPolicy{
+		Window:     max(ipLockoutPolicy.Window, accountLockoutPolicy.Window),
+		ResetAfter: max(ipLockoutPolicy.ResetAfter, accountLockoutPolicy.ResetAfter),
+	}
+)
+
+const loginAttemptsPruneInterval = time.Minute
+
+// LoginGuard ограничивает подбор паролей по IP и по учётной записи.
+// Учётная запись определяется по email независимо от того, существует ли она,
+// поэтому поведение для существующих и несуществующих адресов одинаково.
+type LoginGuard struct {
+	store LoginAttemptStore
+	now   func() time.Time
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+func NewLoginGuard(store LoginAttemptStore) *LoginGuard {
+	if store == nil {
+		panic("login attempt store is required")
+	}
+	return &LoginGuard{store: store, now: time.Now}
+}
+
+// Check возвращает *LoginLockedError, если вход с этого IP или в эту учётную
+// запись временно заблокирован. Вызывается до проверки пароля, чтобы не тратить bcrypt.
+func (g *LoginGuard) Check(ctx context.Context, client models.ClientInfo, email string) error {
+	now := g.now()
+	var lockedUntil time.Time
+
+	for _, key := range g.keys(client, email) {
+		until, err := g.store.LockedUntil(ctx, key, now)
+		if err != nil {
+			// при недоступности хранилища вход не блокируем
+			logging.FromContext(ctx).Error("Ошибка при проверке блокировки входа", "error", err)
+			continue
+		}
+		if until.After(lockedUntil) {
+			lockedUntil = until
+		}
+	}
+
+	if lockedUntil.After(now) {
+		return &LoginLockedError{RetryAfter: lockedUntil.Sub(now)}
+	}
+	return nil
+}
+
+// Failure учитывает неудачную попытку входа
+func (g *LoginGuard) Failure(ctx context.Context, client models.ClientInfo, email string) {
+	now := g.now()
+	logger := logging.FromContext(ctx)
+	logger.Warn("Неудачная попытка входа", "ip", client.IP, "account", accountRef(email))
+
+	for _, key := range g.keys(client, email) {
+		policy := accountLockoutPolicy
+		if strings.HasPrefix(key, "ip:") {
+			policy = ipLockoutPolicy
+		}
+
+		until, err := g.store.RegisterFailure(ctx, key, now, policy)
+		if err != nil {
+			logger.Error("Ошибка при учёте неудачной попытки входа", "error", err)
+			continue
+		}
+		if !until.IsZero() {
+			logger.Warn("Вход временно заблокирован", "key", key, "until", until)
+		}
+	}
+
+	g.prune(ctx, now)
+}
+
+// prune раз в loginAttemptsPruneInterval чистит хранилище: иначе записи
+// для разовых email и IP копились бы бесконечно, особенно при переборе
+func (g *LoginGuard) prune(ctx context.Context, now time.Time) {
+	g.mu.Lock()
+	if now.Sub(g.lastPrune) < loginAttemptsPruneInterval {
+		g.mu.Unlock()
+		return
+	}
+	g.lastPrune = now
+	g.mu.Unlock()
+
+	if err := g.store.Prune(ctx, now, pruneLockoutPolicy); err != nil {
+		logging.FromContext(ctx).Error("Ошибка при очистке попыток входа", "error", err)
+	}
+}
+
+// Success сбрасывает счётчик учётной записи. Счётчик IP не сбрасывается:
+// иначе одна известная пара логин-пароль позволяла бы продолжать перебор.
+func (g *LoginGuard) Success(ctx context.Context, email string) {
+	if err := g.store.Reset(ctx, accountKey(email)); err != nil {
+		logging.FromContext(ctx).Error("Ошибка при сбросе счётчика попыток входа", "error", err)
+	}
+}
+
+func (g *LoginGuard) keys(client models.ClientInfo, email string) []string {
+	keys := make([]string, 0, 2)
+	if client.IP != "" {
+		keys = append(keys, "ip:"+client.IP)
+	}
+	if email != "" {
+		keys = append(keys, accountKey(email))
+	}
+	return keys
+}
+
+// accountKey хранит вместо email его хеш, чтобы в счётчиках не оставались
+// адреса, в том числе несуществующих аккаунтов
+func accountKey(email string) string {
+	return "account:" + hashToken(strings.TrimSpace(strings.ToLower(email)))
+}
+
+// accountRef - короткий идентификатор учётной записи для логов
+func accountRef(email string) string {
+	return accountKey(email)[len("account:"):][:12]
+}
+
+type memoryAttempts struct {
+	failures    []time.Time
+	lockouts    int
+	lockedUntil time.Time
+	lastLockout time.Time
+}
+
+// MemoryLoginAttemptStore хранит счётчики в памяти процесса
+type MemoryLoginAttemptStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryAttempts
+}
+
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{entries: make(map[string]*memoryAttempts)}
+}
+
+func (s *MemoryLoginAttemptStore) LockedUntil(_ context.Context, key string, now time.Time) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || !entry.lockedUntil.After(now) {
+		return time.Time{}, nil
+	}
+	return entry.lockedUntil, nil
+}
+
+func (s *MemoryLoginAttemptStore) RegisterFailure(_ context.Context, key string, now time.Time, policy models.LockoutPolicy) (time.Time, error) {
+	if key == "" {
+		return time.Time{}, fmt.Errorf("пустой ключ попыток входа")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = &memoryAttempts{}
+		s.entries[key] = entry
+	}
+
+	cutoff := now.Add(-policy.Window)
+	recent := entry.failures[:0]
+	for _, t := range entry.failures {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	entry.failures = append(recent, now)
+
+	if len(entry.failures) < policy.Limit {
+		return time.Time{}, nil
+	}
+
+	if now.Sub(entry.lastLockout) > policy.ResetAfter {
+		entry.lockouts = 0
+	}
+	entry.lockouts++
+	entry.lastLockout = now
+	entry.lockedUntil = now.Add(policy.Backoff(entry.lockouts))
+	entry.failures = nil
+
+	return entry.lockedUntil, nil
+}
+
+func (s *MemoryLoginAttemptStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	delete(s.entries, key)
+	s.mu.Unlock()
+	return nil
+}
+
+// Prune удаляет записи без свежих попыток и действующих блокировок
+func (s *MemoryLoginAttemptStore) Prune(_ context.Context, now time.Time, policy models.LockoutPolicy) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, entry := range s.entries {
+		stale := len(entry.failures) == 0 || now.Sub(entry.failures[len(entry.failures)-1]) > policy.Window
+		if stale && now.Sub(entry.lastLockout) > policy.ResetAfter && !entry.lockedUntil.After(now) {
+			delete(s.entries, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"delivery-service/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+var testLockoutPolicy = models.LockoutPolicy{
+	Limit:       3,
+	Window:      10 * time.Minute,
+	BaseLockout: time.Minute,
+	MaxLockout:  4 * time.Minute,
+	ResetAfter:  time.Hour,
+}
+
+func TestMemoryLoginAttemptStoreBackoff(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryLoginAttemptStore()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// каждая строка - серия из Limit неудачных попыток, начатая в at
+	tests := []struct {
+		name string
+		at   time.Duration
+		want time.Duration
+	}{
+		{"первая блокировка", 0, time.Minute},
+		{"вторая вдвое дольше", 2 * time.Minute, 2 * time.Minute},
+		{"третья", 5 * time.Minute, 4 * time.Minute},
+		{"не дольше MaxLockout", 10 * time.Minute, 4 * time.Minute},
+		{"после ResetAfter счёт заново", 2 * time.Hour, time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := start.Add(tt.at)
+			var until time.Time
+			for i := 0; i < testLockoutPolicy.Limit; i++ {
+				var err error
+				until, err = store.RegisterFailure(ctx, "account:x", now, testLockoutPolicy)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if i < testLockoutPolicy.Limit-1 && !until.IsZero() {
+					t.Fatalf("locked after %d failures", i+1)
+				}
+			}
+			if got := until.Sub(now); got != tt.want {
+				t.Errorf("lockout = %s, want %s", got, tt.want)
+			}
+			locked, _ := store.LockedUntil(ctx, "account:x", now.Add(tt.want-time.Second))
+			if !locked.Equal(until) {
+				t.Errorf("LockedUntil before expiry = %v, want %v", locked, until)
+			}
+			if locked, _ := store.LockedUntil(ctx, "account:x", until); !locked.IsZero() {
+				t.Errorf("LockedUntil after expiry = %v, want zero", locked)
+			}
+		})
+	}
+}
+
+func TestMemoryLoginAttemptStoreWindow(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryLoginAttemptStore()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	store.RegisterFailure(ctx, "ip:1.2.3.4", now, testLockoutPolicy)
+	store.RegisterFailure(ctx, "ip:1.2.3.4", now.Add(time.Minute), testLockoutPolicy)
+	// первая попытка выпала из окна, блокировки нет
+	until, _ := store.RegisterFailure(ctx, "ip:1.2.3.4", now.Add(testLockoutPolicy.Window+time.Second), testLockoutPolicy)
+	if !until.IsZero() {
+		t.Fatalf("locked with a failure outside the window")
+	}
+
+	if err := store.Reset(ctx, "ip:1.2.3.4"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.RegisterFailure(ctx, "", now, testLockoutPolicy); err == nil {
+		t.Error("empty key accepted")
+	}
+}
+
+func TestMemoryLoginAttemptStorePrune(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryLoginAttemptStore()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	store.RegisterFailure(ctx, "ip:old", now.Add(-testLockoutPolicy.Window-time.Second), testLockoutPolicy)
+	store.RegisterFailure(ctx, "ip:fresh", now.Add(-time.Minute), testLockoutPolicy)
+	// блокировка истекла, но счётчик блокировок ещё нужен для backoff
+	for i := 0; i < testLockoutPolicy.Limit; i++ {
+		store.RegisterFailure(ctx, "account:recent", now.Add(-30*time.Minute), testLockoutPolicy)
+		store.RegisterFailure(ctx, "account:expired", now.Add(-2*testLockoutPolicy.ResetAfter), testLockoutPolicy)
+	}
+
+	if err := store.Prune(ctx, now, testLockoutPolicy); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]bool{
+		"ip:old":          false,
+		"ip:fresh":        true,
+		"account:recent":  true,
+		"account:expired": false,
+	} {
+		if _, got := store.entries[key]; got != want {
+			t.Errorf("entry %s kept = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestLoginGuard(t *testing.T) {
+	direct := models.ClientInfo{IP: "203.0.113.5"}
+
+	tests := []struct {
+		name      string
+		failures  []loginFailure
+		success   string
+		client    models.ClientInfo
+		email     string
+		wantLocks bool
+	}{
+		{
+			name:      "без попыток",
+			client:    direct,
+			email:     "a@example.com",
+			wantLocks: false,
+		},
+		{
+			name:      "учётная запись после 5 неудач",
+			failures:  repeat(5, direct, "A@example.com "),
+			client:    models.ClientInfo{IP: "198.51.100.7"},
+			email:     "a@example.com",
+			wantLocks: true,
+		},
+		{
+			name:      "успешный вход сбрасывает учётную запись",
+			failures:  repeat(4, direct, "a@example.com"),
+			success:   "a@example.com",
+			client:    direct,
+			email:     "a@example.com",
+			wantLocks: false,
+		},
+		{
+			name:      "IP после 20 неудач по разным адресам",
+			failures:  spread(20, direct),
+			client:    direct,
+			email:     "new@example.com",
+			wantLocks: true,
+		},
+		{
+			name:      "IP не сбрасывается успешным входом",
+			failures:  spread(20, direct),
+			success:   "usera@example.com",
+			client:    direct,
+			email:     "usera@example.com",
+			wantLocks: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			guard := NewLoginGuard(NewMemoryLoginAttemptStore())
+			now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+			guard.now = func() time.Time { return now }
+
+			for _, f := range tt.failures {
+				guard.Failure(ctx, f.client, f.email)
+			}
+			if tt.success != "" {
+				guard.Success(ctx, tt.success)
+			}
+
+			err := guard.Check(ctx, tt.client, tt.email)
+			var locked *LoginLockedError
+			if got := errors.As(err, &locked); got != tt.wantLocks {
+				t.Fatalf("Check() = %v, want locked %v", err, tt.wantLocks)
+			}
+			if tt.wantLocks && (locked.RetryAfter <= 0 || !errors.Is(err, ErrTooManyLoginAttempts)) {
+				t.Errorf("unexpected lock error %+v", locked)
+			}
+		})
+	}
+}
+
+type loginFailure struct {
+	client models.ClientInfo
+	email  string
+}
+
+func repeat(n int, client models.ClientInfo, email string) []loginFailure {
+	failures := make([]loginFailure, n)
+	for i := range failures {
+		failures[i].client, failures[i].email = client, email
+	}
+	return failures
+}
+
+// spread - неудачные попытки с одного адреса в разные учётные записи
+func spread(n int, client models.ClientInfo) []loginFailure {
+	failures := repeat(n, client, "")
+	for i := range failures {
+		failures[i].email = "user" + string(rune('a'+i)) + "@example.com"
+	}
+	return failures
+}
+
+// countingStore считает вызовы Prune
+type countingStore struct {
+	*MemoryLoginAttemptStore
+	prunes int
+}
+
+func (s *countingStore) Prune(ctx context.Context, now time.Time, policy models.LockoutPolicy) error {
+	s.prunes++
+	return s.MemoryLoginAttemptStore.Prune(ctx, now, policy)
+}
+
+func TestLoginGuardPrunesPeriodically(t *testing.T) {
+	ctx := context.Background()
+	store := &countingStore{MemoryLoginAttemptStore: NewMemoryLoginAttemptStore()}
+	guard := NewLoginGuard(store)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	guard.now = func() time.Time { return now }
+
+	client := models.ClientInfo{IP: "203.0.113.5"}
+	guard.Failure(ctx, client, "a@example.com")
+	guard.Failure(ctx, client, "b@example.com")
+	if store.prunes != 1 {
+		t.Fatalf("prunes = %d, want 1 within the interval", store.prunes)
+	}
+
+	now = now.Add(loginAttemptsPruneInterval)
+	guard.Failure(ctx, client, "c@example.com")
+	if store.prunes != 2 {
+		t.Errorf("prunes = %d, want 2 after the interval", store.prunes)
+	}
+}
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"context"
+	"delivery-service/metrics"
+	"delivery-service/models"
+	"delivery-service/repository"
+	"delivery-service/tracing"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrOrderNotFound       = errors.New("заказ не найден")
+	ErrEmptyOrder          = errors.New("заказ должен содержать хотя бы один товар")
+	ErrInvalidOrderItem    = errors.New("для каждого товара нужно указать маркетплейс и корректную ссылку")
+	ErrInvalidQuantity     = errors.New("количество товара должно быть больше нуля")
+	ErrInvalidDelivery     = errors.New("необходимо указать адрес, дату и время доставки")
+	ErrInvalidDeliveryDate = errors.New("неверный формат даты или времени доставки")
+	ErrTooManyOrderItems   = errors.New("заказ не может содержать больше 50 товаров")
+	ErrInvalidOrderStatus  = errors.New("неизвестный статус заказа")
+	ErrStatusTransition    = errors.New("недопустимая смена статуса заказа")
+	ErrStatusConflict      = errors.New("статус заказа был изменён, обновите данные и повторите попытку")
+	ErrOrderNotCancellable = errors.New("заказ уже нельзя отменить")
+)
+
+const maxOrderItems = 50
+
+type OrderService struct {
+	orderRepo *repository.OrderRepository
+}
+
+func NewOrderService(orderRepo *repository.OrderRepository) *OrderService {
+	if orderRepo == nil {
+		panic("order repository is required")
+	}
+	return &OrderService{orderRepo: orderRepo}
+}
+
+func (s *OrderService) CreateOrder(ctx context.Context, userID int64, req *models.CreateOrderRequest) (*models.Order, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.CreateOrder")
+	defer span.End()
+
+	if userID <= 0 {
+		return nil, ErrInvalidInput
+	}
+
+	if err := s.validateCreateRequest(req); err != nil {
+		return nil, err
+	}
+
+	order := &models.Order{
+		UserID: userID,
+		Status: models.OrderStatusCreated,
+		Items:  make([]models.OrderItem, 0, len(req.Items)),
+		DeliveryDetails: models.DeliveryDetails{
+			Address: strings.TrimSpace(req.DeliveryDetails.Address),
+			Date:    req.DeliveryDetails.Date,
+			Time:    req.DeliveryDetails.Time,
+			Notes:   strings.TrimSpace(req.DeliveryDetails.Notes),
+		},
+	}
+
+	for _, item := range req.Items {
+		order.Items = append(order.Items, models.OrderItem{
+			Marketplace: strings.TrimSpace(item.Marketplace),
+			Link:        strings.TrimSpace(item.Link),
+			Quantity:    item.Quantity,
+			Size:        item.Size,
+			Color:       item.Color,
+			Notes:       item.Notes,
+		})
+	}
+
+	if err := s.saveWithTrackingCode(ctx, order, s.orderRepo.CreateOrder); err != nil {
+		return nil, err
+	}
+	metrics.OrdersCreated.WithLabelValues(metrics.OrderSourceDirect).Inc()
+
+	return order, nil
+}
+
+// CheckoutCart оформляет заказ из серверной корзины пользователя
+func (s *OrderService) CheckoutCart(ctx context.Context, userID int64, req *models.CheckoutRequest) (*models.Order, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.CheckoutCart")
+	defer span.End()
+
+	if userID <= 0 || req == nil {
+		return nil, ErrInvalidInput
+	}
+
+	if err := validateDeliveryDetails(&req.DeliveryDetails); err != nil {
+		return nil, err
+	}
+
+	order := &models.Order{
+		UserID: userID,
+		Status: models.OrderStatusCreated,
+		DeliveryDetails: models.DeliveryDetails{
+			Address: strings.TrimSpace(req.DeliveryDetails.Address),
+			Date:    req.DeliveryDetails.Date,
+			Time:    req.DeliveryDetails.Time,
+			Notes:   strings.TrimSpace(req.DeliveryDetails.Notes),
+		},
+	}
+
+	if err := s.saveWithTrackingCode(ctx, order, s.orderRepo.CreateOrderFromCart); err != nil {
+		if errors.Is(err, repository.ErrCartEmpty) {
+			return nil, ErrCartEmpty
+		}
+		return nil, err
+	}
+	metrics.OrdersCreated.WithLabelValues(metrics.OrderSourceCart).Inc()
+
+	return order, nil
+}
+
+// saveWithTrackingCode присваивает заказу трек-номер и сохраняет его,
+// при маловероятной коллизии кода пробует сгенерировать новый
+func (s *OrderService) saveWithTrackingCode(ctx context.Context, order *models.Order, save func(context.Context, *models.Order) error) error {
+	for attempt := 0; ; attempt++ {
+		code, err := generateTrackingCode()
+		if err != nil {
+			return fmt.Errorf("ошибка при генерации трек-номера: %w", err)
+		}
+		order.TrackingCode = code
+
+		err = save(ctx, order)
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, repository.ErrTrackingCodeExists) && attempt < 2 {
+			continue
+		}
+		return fmt.Errorf("ошибка при создании заказа: %w", err)
+	}
+}
+
+func (s *OrderService) GetOrder(ctx context.Context, userID, orderID int64) (*models.Order, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.GetOrder")
+	defer span.End()
+
+	if userID <= 0 || orderID <= 0 {
+		return nil, ErrInvalidInput
+	}
+
+	order, err := s.orderRepo.GetOrderByID(ctx, orderID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("ошибка при получении заказа: %w", err)
+	}
+
+	order.History, err = s.orderRepo.GetStatusEvents(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении истории заказа: %w", err)
+	}
+
+	return order, nil
+}
+
+// ChangeStatus переводит заказ в новый статус согласно таблице orderTransitions.
+// actorID - пользователь, выполнивший переход (nil для системных событий).
+func (s *OrderService) ChangeStatus(ctx context.Context, orderID int64, actorID *int64, req *models.ChangeOrderStatusRequest) (*models.OrderStatusEvent, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.ChangeStatus")
+	defer span.End()
+
+	if orderID <= 0 || req == nil {
+		return nil, ErrInvalidInput
+	}
+	if !IsValidOrderStatus(req.Status) {
+		return nil, ErrInvalidOrderStatus
+	}
+
+	current, err := s.orderRepo.GetOrderStatus(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("ошибка при получении статуса заказа: %w", err)
+	}
+
+	return s.transition(ctx, orderID, current, actorID, req)
+}
+
+// CancelOrder отменяет заказ по запросу владельца
+func (s *OrderService) CancelOrder(ctx context.Context, userID, orderID int64, req *models.CancelOrderRequest) (*models.OrderStatusEvent, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.CancelOrder")
+	defer span.End()
+
+	if userID <= 0 || orderID <= 0 {
+		return nil, ErrInvalidInput
+	}
+
+	order, err := s.orderRepo.GetOrderByID(ctx, orderID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("ошибка при получении заказа: %w", err)
+	}
+
+	if !customerCancellable[order.Status] {
+		return nil, ErrOrderNotCancellable
+	}
+
+	change := &models.ChangeOrderStatusRequest{Status: models.OrderStatusCancelled}
+	if req != nil {
+		change.Comment = req.Comment
+	}
+
+	return s.transition(ctx, order.ID, order.Status, &userID, change)
+}
+
+func (s *OrderService) transition(ctx context.Context, orderID int64, from string, actorID *int64, req *models.ChangeOrderStatusRequest) (*models.OrderStatusEvent, error) {
+	if !CanTransition(from, req.Status) {
+		return nil, ErrStatusTransition
+	}
+
+	event := &models.OrderStatusEvent{
+		OrderID:    orderID,
+		FromStatus: &from,
+		ToStatus:   req.Status,
+		ActorID:    actorID,
+		Location:   trimOptional(req.Location),
+		Comment:    trimOptional(req.Comment),
+	}
+
+	if err := s.orderRepo.UpdateStatus(ctx, event); err != nil {
+		if errors.Is(err, repository.ErrStatusConflict) {
+			return nil, ErrStatusConflict
+		}
+		return nil, fmt.Errorf("ошибка при смене статуса заказа: %w", err)
+	}
+
+	return event, nil
+}
+
+func (s *OrderService) ListOrders(ctx context.Context, userID int64) ([]*models.Order, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.ListOrders")
+	defer span.End()
+
+	if userID <= 0 {
+		return nil, ErrInvalidInput
+	}
+
+	orders, err := s.orderRepo.GetOrdersByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении заказов: %w", err)
+	}
+
+	return orders, nil
+}
+
+func (s *OrderService) validateCreateRequest(req *models.CreateOrderRequest) error {
+	if req == nil {
+		return ErrInvalidInput
+	}
+
+	if len(req.Items) == 0 {
+		return ErrEmptyOrder
+	}
+	if len(req.Items) > maxOrderItems {
+		return ErrTooManyOrderItems
+	}
+
+	for _, item := range req.Items {
+		if err := validateOrderItem(&item); err != nil {
+			return err
+		}
+	}
+
+	return validateDeliveryDetails(&req.DeliveryDetails)
+}
+
+func validateOrderItem(item *models.OrderItemRequest) error {
+	if strings.TrimSpace(item.Marketplace) == "" {
+		return ErrInvalidOrderItem
+	}
+
+	link, err := url.ParseRequestURI(strings.TrimSpace(item.Link))
+	if err != nil || (link.Scheme != "http" && link.Scheme != "https") || link.Host == "" {
+		return ErrInvalidOrderItem
+	}
+
+	if item.Quantity <= 0 {
+		return ErrInvalidQuantity
+	}
+
+	return nil
+}
+
+func validateDeliveryDetails(details *models.DeliveryDetails) error {
+	if strings.TrimSpace(details.Address) == "" || details.Date == "" || details.Time == "" {
+		return ErrInvalidDelivery
+	}
+
+	if _, err := time.Parse("2006-01-02", details.Date); err != nil {
+		return ErrInvalidDeliveryDate
+	}
+	if _, err := time.Parse("15:04", details.Time); err != nil {
+		return ErrInvalidDeliveryDate
+	}
+
+	return nil
+}
+
+func trimOptional(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
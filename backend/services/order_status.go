@@ -0,0 +1,67 @@
+package services
+
+import (
+	"delivery-service/models"
+)
+
+// orderTransitions - разрешённые переходы между статусами заказа.
+// Статусы, которых нет среди ключей, считаются конечными.
+var orderTransitions = map[string][]string{
+	models.OrderStatusCreated: {
+		models.OrderStatusAccepted,
+		models.OrderStatusCancelled,
+	},
+	models.OrderStatusAccepted: {
+		models.OrderStatusPurchased,
+		models.OrderStatusCancelled,
+	},
+	models.OrderStatusPurchased: {
+		models.OrderStatusAtWarehouse,
+		models.OrderStatusCancelled,
+	},
+	models.OrderStatusAtWarehouse: {
+		models.OrderStatusInTransit,
+		models.OrderStatusReturned,
+	},
+	models.OrderStatusInTransit: {
+		models.OrderStatusAtWarehouse,
+		models.OrderStatusOutForDelivery,
+		models.OrderStatusReadyForPickup,
+		models.OrderStatusReturned,
+	},
+	models.OrderStatusOutForDelivery: {
+		models.OrderStatusDelivered,
+		models.OrderStatusAtWarehouse,
+		models.OrderStatusReturned,
+	},
+	models.OrderStatusReadyForPickup: {
+		models.OrderStatusDelivered,
+		models.OrderStatusReturned,
+	},
+	models.OrderStatusDelivered: {
+		models.OrderStatusReturned,
+	},
+}
+
+// customerCancellable - статусы, в которых клиент может сам отменить заказ
+// (пока товар ещё не выкуплен на маркетплейсе)
+var customerCancellable = map[string]bool{
+	models.OrderStatusCreated:  true,
+	models.OrderStatusAccepted: true,
+}
+
+func IsValidOrderStatus(status string) bool {
+	if _, ok := orderTransitions[status]; ok {
+		return true
+	}
+	return status == models.OrderStatusCancelled || status == models.OrderStatusReturned
+}
+
+func CanTransition(from, to string) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
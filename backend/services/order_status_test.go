@@ -0,0 +1,98 @@
+package services
+
+import (
+	"delivery-service/models"
+	"testing"
+)
+
+var allOrderStatuses = []string{
+	models.OrderStatusCreated,
+	models.OrderStatusAccepted,
+	models.OrderStatusPurchased,
+	models.OrderStatusAtWarehouse,
+	models.OrderStatusInTransit,
+	models.OrderStatusOutForDelivery,
+	models.OrderStatusReadyForPickup,
+	models.OrderStatusDelivered,
+	models.OrderStatusCancelled,
+	models.OrderStatusReturned,
+}
+
+// Проверяются все пары статусов: всё, чего нет в таблице, запрещено
+func TestCanTransition(t *testing.T) {
+	allowed := map[[2]string]bool{
+		{models.OrderStatusCreated, models.OrderStatusAccepted}:           true,
+		{models.OrderStatusCreated, models.OrderStatusCancelled}:          true,
+		{models.OrderStatusAccepted, models.OrderStatusPurchased}:         true,
+		{models.OrderStatusAccepted, models.OrderStatusCancelled}:         true,
+		{models.OrderStatusPurchased, models.OrderStatusAtWarehouse}:      true,
+		{models.OrderStatusPurchased, models.OrderStatusCancelled}:        true,
+		{models.OrderStatusAtWarehouse, models.OrderStatusInTransit}:      true,
+		{models.OrderStatusAtWarehouse, models.OrderStatusReturned}:       true,
+		{models.OrderStatusInTransit, models.OrderStatusAtWarehouse}:      true,
+		{models.OrderStatusInTransit, models.OrderStatusOutForDelivery}:   true,
+		{models.OrderStatusInTransit, models.OrderStatusReadyForPickup}:   true,
+		{models.OrderStatusInTransit, models.OrderStatusReturned}:         true,
+		{models.OrderStatusOutForDelivery, models.OrderStatusDelivered}:   true,
+		{models.OrderStatusOutForDelivery, models.OrderStatusAtWarehouse}: true,
+		{models.OrderStatusOutForDelivery, models.OrderStatusReturned}:    true,
+		{models.OrderStatusReadyForPickup, models.OrderStatusDelivered}:   true,
+		{models.OrderStatusReadyForPickup, models.OrderStatusReturned}:    true,
+		{models.OrderStatusDelivered, models.OrderStatusReturned}:         true,
+	}
+
+	for _, from := range allOrderStatuses {
+		for _, to := range allOrderStatuses {
+			want := allowed[[2]string{from, to}]
+			if got := CanTransition(from, to); got != want {
+				t.Errorf("CanTransition(%s, %s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+
+	for _, tt := range []struct{ from, to string }{
+		{"", models.OrderStatusAccepted},
+		{models.OrderStatusCreated, ""},
+		{"unknown", models.OrderStatusCancelled},
+		{models.OrderStatusCreated, "unknown"},
+	} {
+		if CanTransition(tt.from, tt.to) {
+			t.Errorf("CanTransition(%q, %q) = true", tt.from, tt.to)
+		}
+	}
+}
+
+func TestIsValidOrderStatus(t *testing.T) {
+	for _, status := range allOrderStatuses {
+		if !IsValidOrderStatus(status) {
+			t.Errorf("IsValidOrderStatus(%s) = false", status)
+		}
+	}
+	for _, status := range []string{"", "unknown", "Created", " created"} {
+		if IsValidOrderStatus(status) {
+			t.Errorf("IsValidOrderStatus(%q) = true", status)
+		}
+	}
+}
+
+func TestCustomerCancellable(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{models.OrderStatusCreated, true},
+		{models.OrderStatusAccepted, true},
+		{models.OrderStatusPurchased, false},
+		{models.OrderStatusInTransit, false},
+		{models.OrderStatusCancelled, false},
+	}
+	for _, tt := range tests {
+		if got := customerCancellable[tt.status]; got != tt.want {
+			t.Errorf("customerCancellable[%s] = %v, want %v", tt.status, got, tt.want)
+		}
+		// отмена клиентом не должна обходить общую таблицу переходов
+		if tt.want && !CanTransition(tt.status, models.OrderStatusCancelled) {
+			t.Errorf("%s is customer-cancellable but cannot transition to cancelled", tt.status)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"delivery-service/config"
+	"delivery-service/i18n"
+	"delivery-service/logging"
+	"delivery-service/mailer"
+	"delivery-service/models"
+	"delivery-service/repository"
+	"delivery-service/tracing"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidResetToken    = errors.New("ссылка для сброса пароля недействительна или устарела")
+	ErrWrongCurrentPassword = errors.New("текущий пароль указан неверно")
+	ErrSamePassword         = errors.New("новый пароль должен отличаться от текущего")
+)
+
+const (
+	resetTokenExpiresIn = time.Hour
+	// не является корректным bcrypt-хешем, поэтому вход по паролю невозможен
+	unusablePasswordHash = "!"
+)
+
+type PasswordService struct {
+	userRepo    *repository.UserRepository
+	resetRepo   *repository.PasswordResetRepository
+	sessionRepo *repository.SessionRepository
+	auditRepo   *repository.AuditRepository
+	mailer      *mailer.AsyncMailer
+	appURL      string
+}
+
+func NewPasswordService(
+	cfg *config.Config,
+	userRepo *repository.UserRepository,
+	resetRepo *repository.PasswordResetRepository,
+	sessionRepo *repository.SessionRepository,
+	auditRepo *repository.AuditRepository,
+	m *mailer.AsyncMailer,
+) *PasswordService {
+	if userRepo == nil || resetRepo == nil || sessionRepo == nil || auditRepo == nil || m == nil {
+		panic("password service dependencies are required")
+	}
+
+	return &PasswordService{
+		userRepo:    userRepo,
+		resetRepo:   resetRepo,
+		sessionRepo: sessionRepo,
+		auditRepo:   auditRepo,
+		mailer:      m,
+		appURL:      cfg.Server.AppURL,
+	}
+}
+
+// ForgotPassword отправляет ссылку для сброса пароля. Для неизвестного email
+// ошибка не возвращается, чтобы нельзя было проверить наличие аккаунта.
+func (s *PasswordService) ForgotPassword(ctx context.Context, email string) error {
+	ctx, span := tracing.Start(ctx, "PasswordService.ForgotPassword")
+	defer span.End()
+
+	email = strings.TrimSpace(strings.ToLower(email))
+	if !emailRegex.MatchString(email) {
+		return ErrInvalidEmail
+	}
+
+	user, err := s.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil
+		}
+		return fmt.Errorf("ошибка при поиске пользователя: %w", err)
+	}
+
+	// ошибку отправки не возвращаем: для неизвестного email её быть не может,
+	// и ответ 500 выдал бы, что аккаунт существует
+	if err := s.sendResetLink(ctx, i18n.Preferred(user.Language, i18n.FromContext(ctx)), user, "email.reset.body"); err != nil {
+		logging.FromContext(ctx).Error("Ошибка при отправке ссылки для сброса пароля", "user_id", user.ID, "error", err)
+	}
+	return nil
+}
+
+// ForcePasswordReset по решению администратора делает текущий пароль
+// недействительным, завершает все сеансы и отправляет ссылку для сброса
+func (s *PasswordService) ForcePasswordReset(ctx context.Context, actorID, userID int64, client models.ClientInfo) error {
+	ctx, span := tracing.Start(ctx, "PasswordService.ForcePasswordReset")
+	defer span.End()
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("ошибка при получении пользователя: %w", err)
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, userID, unusablePasswordHash); err != nil {
+		return fmt.Errorf("ошибка при сбросе пароля: %w", err)
+	}
+
+	if err := s.sessionRepo.RevokeAllSessions(ctx, userID, 0, repository.RevokeReasonPasswordReset); err != nil {
+		return fmt.Errorf("ошибка при завершении сеансов: %w", err)
+	}
+
+	// язык запроса здесь - язык администратора, поэтому без настройки в профиле письмо уходит на языке по умолчанию
+	if err := s.sendResetLink(ctx, i18n.Preferred(user.Language, i18n.Default), user, "email.reset_forced.body"); err != nil {
+		return err
+	}
+
+	recordAdminAudit(ctx, s.auditRepo, actorID, userID, models.AuditPasswordForced, client, nil)
+	return nil
+}
+
+// sendResetLink создаёт токен сброса и асинхронно отправляет письмо со ссылкой.
+// bodyKey - ключ текста письма в каталоге, ссылка подставляется в {link}.
+func (s *PasswordService) sendResetLink(ctx context.Context, lang i18n.Lang, user *models.User, bodyKey string) error {
+	token, tokenHash, err := generateOpaqueToken()
+	if err != nil {
+		return fmt.Errorf("ошибка при генерации токена сброса: %w", err)
+	}
+
+	if err := s.resetRepo.CreateToken(ctx, user.ID, tokenHash, time.Now().Add(resetTokenExpiresIn)); err != nil {
+		return fmt.Errorf("ошибка при сохранении токена сброса: %w", err)
+	}
+
+	link := s.appURL + "/reset-password?token=" + url.QueryEscape(token)
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: i18n.T(lang, "email.reset.subject"),
+		Body:    i18n.T(lang, "email.greeting", "name", user.Name) + "\n\n" + i18n.T(lang, bodyKey, "link", link),
+	}
+
+	// отправляем асинхронно: время ответа не должно зависеть от существования аккаунта
+	if err := s.mailer.Send(msg); err != nil {
+		return fmt.Errorf("ошибка при отправке письма для сброса пароля: %w", err)
+	}
+
+	return nil
+}
+
+// ResetPassword задаёт новый пароль по одноразовому токену и завершает все сеансы
+func (s *PasswordService) ResetPassword(ctx context.Context, token, password, confirmPassword string, client models.ClientInfo) error {
+	ctx, span := tracing.Start(ctx, "PasswordService.ResetPassword")
+	defer span.End()
+
+	if token == "" {
+		return ErrInvalidResetToken
+	}
+	if err := validateNewPassword(password, confirmPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := hashPassword(ctx, password)
+	if err != nil {
+		return fmt.Errorf("ошибка при хешировании пароля: %w", err)
+	}
+
+	userID, err := s.resetRepo.ResetPassword(ctx, hashToken(token), string(hashedPassword))
+	if err != nil {
+		if errors.Is(err, repository.ErrResetTokenInvalid) {
+			return ErrInvalidResetToken
+		}
+		return fmt.Errorf("ошибка при сбросе пароля: %w", err)
+	}
+
+	recordAudit(ctx, s.auditRepo, userID, models.AuditPasswordReset, client)
+	return nil
+}
+
+// ChangePassword меняет пароль авторизованного пользователя и завершает
+// все его сеансы, кроме текущего
+func (s *PasswordService) ChangePassword(ctx context.Context, userID, sessionID int64, req *models.ChangePasswordRequest, client models.ClientInfo) error {
+	ctx, span := tracing.Start(ctx, "PasswordService.ChangePassword")
+	defer span.End()
+
+	if userID <= 0 || req == nil {
+		return ErrInvalidInput
+	}
+	if req.CurrentPassword == "" {
+		return ErrWrongCurrentPassword
+	}
+	if err := validateNewPassword(req.NewPassword, req.ConfirmPassword); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("ошибка при получении пользователя: %w", err)
+	}
+
+	if err := comparePassword(ctx, []byte(user.PasswordHash), req.CurrentPassword); err != nil {
+		return ErrWrongCurrentPassword
+	}
+	if req.NewPassword == req.CurrentPassword {
+		return ErrSamePassword
+	}
+
+	hashedPassword, err := hashPassword(ctx, req.NewPassword)
+	if err != nil {
+		return fmt.Errorf("ошибка при хешировании пароля: %w", err)
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, userID, string(hashedPassword)); err != nil {
+		return fmt.Errorf("ошибка при обновлении пароля: %w", err)
+	}
+
+	if err := s.sessionRepo.RevokeAllSessions(ctx, userID, sessionID, repository.RevokeReasonPassword); err != nil {
+		return fmt.Errorf("ошибка при завершении сеансов: %w", err)
+	}
+
+	recordAudit(ctx, s.auditRepo, userID, models.AuditPasswordChanged, client)
+	return nil
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"delivery-service/models"
+	"errors"
+	"testing"
+)
+
+func TestValidateNewPassword(t *testing.T) {
+	tests := []struct {
+		name              string
+		password, confirm string
+		want              error
+	}{
+		{"подходящий пароль", "correct horse", "correct horse", nil},
+		{"ровно 8 символов", "12345678", "12345678", nil},
+		{"короткий пароль", "1234567", "1234567", ErrInvalidPassword},
+		{"пароли не совпадают", "correct horse", "correct horsE", ErrPasswordMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateNewPassword(tt.password, tt.confirm); !errors.Is(err, tt.want) {
+				t.Errorf("validateNewPassword() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// некорректный запрос отклоняется до обращения к базе
+func TestChangePasswordValidation(t *testing.T) {
+	s := &PasswordService{}
+	tests := []struct {
+		name   string
+		userID int64
+		req    *models.ChangePasswordRequest
+		want   error
+	}{
+		{"без запроса", 3, nil, ErrInvalidInput},
+		{"без пользователя", 0, &models.ChangePasswordRequest{CurrentPassword: "old password", NewPassword: "new password", ConfirmPassword: "new password"}, ErrInvalidInput},
+		{"без текущего пароля", 3, &models.ChangePasswordRequest{NewPassword: "new password", ConfirmPassword: "new password"}, ErrWrongCurrentPassword},
+		{"короткий новый пароль", 3, &models.ChangePasswordRequest{CurrentPassword: "old password", NewPassword: "short", ConfirmPassword: "short"}, ErrInvalidPassword},
+		{"подтверждение не совпадает", 3, &models.ChangePasswordRequest{CurrentPassword: "old password", NewPassword: "new password", ConfirmPassword: "other password"}, ErrPasswordMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.ChangePassword(context.Background(), tt.userID, 7, tt.req, models.ClientInfo{})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("ChangePassword() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
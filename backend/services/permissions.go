@@ -0,0 +1,52 @@
+package services
+
+import (
+	"delivery-service/models"
+)
+
+// rolePermissions - права каждой роли. Клиенту дополнительные права не нужны:
+// доступ к своим данным проверяется по владельцу.
+var rolePermissions = map[string][]string{
+	models.RoleCustomer: {},
+	models.RoleCourier: {
+		models.PermOrdersDeliver,
+	},
+	models.RoleOperator: {
+		models.PermOrdersManage,
+		models.PermPickupPointsManage,
+	},
+	models.RoleAdmin: {
+		models.PermOrdersManage,
+		models.PermPickupPointsManage,
+		models.PermUsersManage,
+	},
+}
+
+// courierStatuses - статусы, которые может выставить курьер
+var courierStatuses = map[string]bool{
+	models.OrderStatusOutForDelivery: true,
+	models.OrderStatusDelivered:      true,
+	models.OrderStatusReturned:       true,
+}
+
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+func HasPermission(role, permission string) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// CanSetOrderStatus проверяет, может ли роль перевести заказ в статус
+func CanSetOrderStatus(role, status string) bool {
+	if HasPermission(role, models.PermOrdersManage) {
+		return true
+	}
+	return HasPermission(role, models.PermOrdersDeliver) && courierStatuses[status]
+}
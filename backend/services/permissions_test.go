@@ -0,0 +1,63 @@
+package services
+
+import (
+	"delivery-service/models"
+	"testing"
+)
+
+func TestHasPermission(t *testing.T) {
+	tests := []struct {
+		role, permission string
+		want             bool
+	}{
+		{models.RoleAdmin, models.PermUsersManage, true},
+		{models.RoleAdmin, models.PermOrdersManage, true},
+		{models.RoleOperator, models.PermPickupPointsManage, true},
+		// оператор не управляет пользователями
+		{models.RoleOperator, models.PermUsersManage, false},
+		{models.RoleCourier, models.PermOrdersDeliver, true},
+		{models.RoleCourier, models.PermOrdersManage, false},
+		{models.RoleCustomer, models.PermOrdersManage, false},
+		{"superuser", models.PermUsersManage, false},
+	}
+	for _, tt := range tests {
+		if got := HasPermission(tt.role, tt.permission); got != tt.want {
+			t.Errorf("HasPermission(%q, %q) = %v, want %v", tt.role, tt.permission, got, tt.want)
+		}
+	}
+}
+
+func TestCanSetOrderStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		role, status string
+		want         bool
+	}{
+		{"оператор принимает заказ", models.RoleOperator, models.OrderStatusAccepted, true},
+		{"администратор отменяет заказ", models.RoleAdmin, models.OrderStatusCancelled, true},
+		{"курьер везёт заказ", models.RoleCourier, models.OrderStatusOutForDelivery, true},
+		{"курьер вручает заказ", models.RoleCourier, models.OrderStatusDelivered, true},
+		{"курьер возвращает заказ", models.RoleCourier, models.OrderStatusReturned, true},
+		{"курьер не отменяет заказ", models.RoleCourier, models.OrderStatusCancelled, false},
+		{"курьер не принимает заказ", models.RoleCourier, models.OrderStatusAccepted, false},
+		{"клиент не меняет статус", models.RoleCustomer, models.OrderStatusDelivered, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanSetOrderStatus(tt.role, tt.status); got != tt.want {
+				t.Errorf("CanSetOrderStatus(%q, %q) = %v, want %v", tt.role, tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidRole(t *testing.T) {
+	for _, role := range []string{models.RoleCustomer, models.RoleCourier, models.RoleOperator, models.RoleAdmin} {
+		if !IsValidRole(role) {
+			t.Errorf("IsValidRole(%q) = false", role)
+		}
+	}
+	if IsValidRole("Admin") || IsValidRole("") {
+		t.Error("unknown role reported valid")
+	}
+}
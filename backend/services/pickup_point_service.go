@@ -0,0 +1,335 @@
+package services
+
+import (
+	"context"
+	"delivery-service/models"
+	"delivery-service/repository"
+	"delivery-service/tracing"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrPickupPointNotFound  = errors.New("пункт выдачи не найден")
+	ErrInvalidPickupPoint   = errors.New("необходимо указать название, адрес и город пункта выдачи")
+	ErrInvalidCoordinates   = errors.New("некорректные координаты")
+	ErrInvalidTimezone      = errors.New("неизвестный часовой пояс")
+	ErrInvalidWorkingHours  = errors.New("некорректное расписание работы (требуется HH:MM для дней mon..sun)")
+	ErrInvalidCapacity      = errors.New("вместимость не может быть отрицательной")
+	ErrInvalidSearchRadius  = errors.New("радиус поиска должен быть от 1 м до 100 км")
+	ErrIncompleteCoordinate = errors.New("для поиска нужно указать и широту, и долготу")
+)
+
+const (
+	defaultPickupTimezone   = "Europe/Moscow"
+	defaultSearchRadius     = 10000.0
+	maxSearchRadius         = 100000.0
+	defaultPickupPointLimit = 100
+	earthRadiusMeters       = 6371000.0
+)
+
+type PickupPointService struct {
+	pointRepo *repository.PickupPointRepository
+}
+
+func NewPickupPointService(pointRepo *repository.PickupPointRepository) *PickupPointService {
+	if pointRepo == nil {
+		panic("pickup point repository is required")
+	}
+	return &PickupPointService{pointRepo: pointRepo}
+}
+
+// Search возвращает активные пункты выдачи. Если заданы координаты,
+// результат ограничен радиусом и отсортирован по расстоянию.
+func (s *PickupPointService) Search(ctx context.Context, query *models.PickupPointQuery) ([]*models.PickupPoint, error) {
+	ctx, span := tracing.Start(ctx, "PickupPointService.Search")
+	defer span.End()
+
+	if query == nil {
+		return nil, ErrInvalidInput
+	}
+
+	limit := query.Limit
+	if limit <= 0 || limit > defaultPickupPointLimit {
+		limit = defaultPickupPointLimit
+	}
+
+	var (
+		points []*models.PickupPoint
+		err    error
+	)
+
+	switch {
+	case query.Latitude == nil && query.Longitude == nil:
+		points, err = s.pointRepo.List(ctx, false, limit)
+	case query.Latitude == nil || query.Longitude == nil:
+		return nil, ErrIncompleteCoordinate
+	default:
+		lat, lon := *query.Latitude, *query.Longitude
+		if !validCoordinates(lat, lon) {
+			return nil, ErrInvalidCoordinates
+		}
+
+		radius := query.RadiusMeters
+		if radius == 0 {
+			radius = defaultSearchRadius
+		}
+		if radius < 1 || radius > maxSearchRadius {
+			return nil, ErrInvalidSearchRadius
+		}
+
+		minLat, maxLat, minLon, maxLon := boundingBox(lat, lon, radius)
+		points, err = s.pointRepo.FindNearby(ctx, lat, lon, radius, minLat, maxLat, minLon, maxLon, limit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при поиске пунктов выдачи: %w", err)
+	}
+
+	s.markOpen(points)
+	return points, nil
+}
+
+func (s *PickupPointService) ListAll(ctx context.Context) ([]*models.PickupPoint, error) {
+	ctx, span := tracing.Start(ctx, "PickupPointService.ListAll")
+	defer span.End()
+
+	points, err := s.pointRepo.List(ctx, true, math.MaxInt32)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении пунктов выдачи: %w", err)
+	}
+
+	s.markOpen(points)
+	return points, nil
+}
+
+func (s *PickupPointService) GetPickupPoint(ctx context.Context, id int64, includeInactive bool) (*models.PickupPoint, error) {
+	ctx, span := tracing.Start(ctx, "PickupPointService.GetPickupPoint")
+	defer span.End()
+
+	point, err := s.pointRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrPickupPointNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+			return nil, ErrPickupPointNotFound
+		}
+		return nil, fmt.Errorf("ошибка при получении пункта выдачи: %w", err)
+	}
+	if !point.IsActive && !includeInactive {
+		return nil, ErrPickupPointNotFound
+	}
+
+	s.markOpen([]*models.PickupPoint{point})
+	return point, nil
+}
+
+func (s *PickupPointService) CreatePickupPoint(ctx context.Context, req *models.PickupPointRequest) (*models.PickupPoint, error) {
+	ctx, span := tracing.Start(ctx, "PickupPointService.CreatePickupPoint")
+	defer span.End()
+
+	point, err := s.buildPickupPoint(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.pointRepo.Create(ctx, point); err != nil {
+		return nil, fmt.Errorf("ошибка при создании пункта выдачи: %w", err)
+	}
+
+	s.markOpen([]*models.PickupPoint{point})
+	return point, nil
+}
+
+func (s *PickupPointService) UpdatePickupPoint(ctx context.Context, id int64, req *models.PickupPointRequest) (*models.PickupPoint, error) {
+	ctx, span := tracing.Start(ctx, "PickupPointService.UpdatePickupPoint")
+	defer span.End()
+
+	point, err := s.buildPickupPoint(req)
+	if err != nil {
+		return nil, err
+	}
+	point.ID = id
+
+	if err := s.pointRepo.Update(ctx, point); err != nil {
+		if errors.Is(err, repository.ErrPickupPointNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+			return nil, ErrPickupPointNotFound
+		}
+		return nil, fmt.Errorf("ошибка при обновлении пункта выдачи: %w", err)
+	}
+
+	s.markOpen([]*models.PickupPoint{point})
+	return point, nil
+}
+
+func (s *PickupPointService) DeletePickupPoint(ctx context.Context, id int64) error {
+	ctx, span := tracing.Start(ctx, "PickupPointService.DeletePickupPoint")
+	defer span.End()
+
+	if err := s.pointRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrPickupPointNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+			return ErrPickupPointNotFound
+		}
+		return fmt.Errorf("ошибка при удалении пункта выдачи: %w", err)
+	}
+	return nil
+}
+
+func (s *PickupPointService) buildPickupPoint(req *models.PickupPointRequest) (*models.PickupPoint, error) {
+	if req == nil {
+		return nil, ErrInvalidInput
+	}
+
+	point := &models.PickupPoint{
+		Name:         strings.TrimSpace(req.Name),
+		Address:      strings.TrimSpace(req.Address),
+		City:         strings.TrimSpace(req.City),
+		Timezone:     strings.TrimSpace(req.Timezone),
+		WorkingHours: req.WorkingHours,
+		Phone:        trimOptional(req.Phone),
+		Capacity:     req.Capacity,
+		IsActive:     true,
+	}
+	if req.IsActive != nil {
+		point.IsActive = *req.IsActive
+	}
+	if point.Timezone == "" {
+		point.Timezone = defaultPickupTimezone
+	}
+	if point.WorkingHours == nil {
+		point.WorkingHours = models.WorkingHours{}
+	}
+
+	if point.Name == "" || point.Address == "" || point.City == "" {
+		return nil, ErrInvalidPickupPoint
+	}
+	if req.Latitude == nil || req.Longitude == nil || !validCoordinates(*req.Latitude, *req.Longitude) {
+		return nil, ErrInvalidCoordinates
+	}
+	point.Latitude, point.Longitude = *req.Latitude, *req.Longitude
+
+	if _, err := time.LoadLocation(point.Timezone); err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	if err := validateWorkingHours(point.WorkingHours); err != nil {
+		return nil, err
+	}
+	if point.Capacity < 0 {
+		return nil, ErrInvalidCapacity
+	}
+
+	return point, nil
+}
+
+// markOpen выставляет OpenNow по местному времени каждого пункта
+func (s *PickupPointService) markOpen(points []*models.PickupPoint) {
+	now := time.Now()
+	for _, point := range points {
+		point.OpenNow = point.IsActive && isOpenAt(point, now)
+	}
+}
+
+func isOpenAt(point *models.PickupPoint, at time.Time) bool {
+	loc, err := time.LoadLocation(point.Timezone)
+	if err != nil {
+		return false
+	}
+
+	local := at.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	today := models.Weekdays[local.Weekday()]
+	yesterday := models.Weekdays[(local.Weekday()+6)%7]
+
+	if hours, ok := point.WorkingHours[today]; ok {
+		open, _ := parseClock(hours.Open)
+		closeAt, _ := parseClock(hours.Close)
+		if closeAt > open && minute >= open && minute < closeAt {
+			return true
+		}
+		// смена переходит через полночь
+		if closeAt <= open && minute >= open {
+			return true
+		}
+	}
+
+	if hours, ok := point.WorkingHours[yesterday]; ok {
+		open, _ := parseClock(hours.Open)
+		closeAt, _ := parseClock(hours.Close)
+		if closeAt <= open && minute < closeAt {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateWorkingHours(hours models.WorkingHours) error {
+	for day, h := range hours {
+		known := false
+		for _, weekday := range models.Weekdays {
+			if day == weekday {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return ErrInvalidWorkingHours
+		}
+
+		open, err := parseClock(h.Open)
+		if err != nil || open == 24*60 {
+			return ErrInvalidWorkingHours
+		}
+		if _, err := parseClock(h.Close); err != nil {
+			return ErrInvalidWorkingHours
+		}
+	}
+	return nil
+}
+
+// parseClock переводит HH:MM в минуты от полуночи, допускается 24:00
+func parseClock(value string) (int, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 2 || len(parts[0]) != 2 || len(parts[1]) != 2 {
+		return 0, ErrInvalidWorkingHours
+	}
+
+	h, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, ErrInvalidWorkingHours
+	}
+	m, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, ErrInvalidWorkingHours
+	}
+
+	if h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, ErrInvalidWorkingHours
+	}
+	return h*60 + m, nil
+}
+
+func validCoordinates(lat, lon float64) bool {
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
+
+// boundingBox возвращает прямоугольник, гарантированно содержащий круг радиуса radius.
+// Рядом с полюсами и линией перемены дат долгота не ограничивается.
+func boundingBox(lat, lon, radius float64) (minLat, maxLat, minLon, maxLon float64) {
+	deltaLat := radius / earthRadiusMeters * 180 / math.Pi
+	minLat = math.Max(lat-deltaLat, -90)
+	maxLat = math.Min(lat+deltaLat, 90)
+
+	if maxLat >= 90 || minLat <= -90 {
+		return minLat, maxLat, -180, 180
+	}
+
+	deltaLon := deltaLat / math.Cos(lat*math.Pi/180)
+	minLon, maxLon = lon-deltaLon, lon+deltaLon
+	if minLon < -180 || maxLon > 180 {
+		return minLat, maxLat, -180, 180
+	}
+
+	return minLat, maxLat, minLon, maxLon
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"delivery-service/models"
+	"errors"
+	"math"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestIsOpenAt(t *testing.T) {
+	point := &models.PickupPoint{
+		Timezone: "Europe/Moscow",
+		WorkingHours: models.WorkingHours{
+			"mon": {Open: "09:00", Close: "18:00"},
+			// смена через полночь: закрывается в 02:00 субботы
+			"fri": {Open: "22:00", Close: "02:00"},
+			"sun": {Open: "00:00", Close: "24:00"},
+		},
+	}
+	// 1 января 2024 года - понедельник, в Москве UTC+3
+	msk := time.FixedZone("MSK", 3*60*60)
+	at := func(day, hour, minute int) time.Time { return time.Date(2024, 1, day, hour, minute, 0, 0, msk) }
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"до открытия", at(1, 8, 59), false},
+		{"открытие", at(1, 9, 0), true},
+		{"последняя минута", at(1, 17, 59), true},
+		{"закрытие", at(1, 18, 0), false},
+		{"выходной", at(2, 12, 0), false},
+		{"ночная смена до полуночи", at(5, 23, 0), true},
+		{"ночная смена после полуночи", at(6, 1, 59), true},
+		{"ночная смена закончилась", at(6, 2, 0), false},
+		{"пятница до ночной смены", at(5, 21, 59), false},
+		{"круглосуточно", at(7, 23, 59), true},
+		// время пункта, а не сервера: 06:30 UTC - это 09:30 в Москве
+		{"часовой пояс пункта", time.Date(2024, 1, 1, 6, 30, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isOpenAt(point, tt.at); got != tt.want {
+				t.Errorf("isOpenAt(%s) = %v, want %v", tt.at.Format(time.RFC3339), got, tt.want)
+			}
+		})
+	}
+
+	unknown := &models.PickupPoint{Timezone: "Mars/Olympus", WorkingHours: point.WorkingHours}
+	if isOpenAt(unknown, at(1, 12, 0)) {
+		t.Error("point with unknown timezone reported open")
+	}
+}
+
+// destination возвращает точку на расстоянии distance по направлению bearing (в градусах)
+func destination(lat, lon, distance, bearing float64) (float64, float64) {
+	toRad := math.Pi / 180
+	d := distance / earthRadiusMeters
+	lat1, lon1, b := lat*toRad, lon*toRad, bearing*toRad
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(d) + math.Cos(lat1)*math.Sin(d)*math.Cos(b))
+	lon2 := lon1 + math.Atan2(math.Sin(b)*math.Sin(d)*math.Cos(lat1), math.Cos(d)-math.Sin(lat1)*math.Sin(lat2))
+	// долгота приводится к [-180, 180]
+	return lat2 / toRad, math.Remainder(lon2/toRad, 360)
+}
+
+func TestBoundingBox(t *testing.T) {
+	tests := []struct {
+		name          string
+		lat, lon      float64
+		radius        float64
+		wantWholeLons bool
+	}{
+		{"Москва", 55.7558, 37.6173, 10000, false},
+		{"экватор", 0, 0, 100000, false},
+		{"южное полушарие", -33.8688, 151.2093, 50000, false},
+		{"рядом с полюсом", 89.95, 10, 10000, true},
+		{"линия перемены дат", 64.7, 179.99, 10000, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minLat, maxLat, minLon, maxLon := boundingBox(tt.lat, tt.lon, tt.radius)
+			if minLat < -90 || maxLat > 90 || minLat > tt.lat || maxLat < tt.lat {
+				t.Fatalf("latitude range [%g, %g] is invalid for %g", minLat, maxLat, tt.lat)
+			}
+			if wholeLons := minLon == -180 && maxLon == 180; wholeLons != tt.wantWholeLons {
+				t.Errorf("longitude range [%g, %g], want whole range = %v", minLon, maxLon, tt.wantWholeLons)
+			}
+
+			// все точки окружности радиуса radius попадают в прямоугольник
+			for bearing := 0.0; bearing < 360; bearing += 15 {
+				lat, lon := destination(tt.lat, tt.lon, tt.radius, bearing)
+				if lat < minLat || lat > maxLat || lon < minLon || lon > maxLon {
+					t.Errorf("point (%g, %g) at bearing %g is outside [%g..%g, %g..%g]", lat, lon, bearing, minLat, maxLat, minLon, maxLon)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateWorkingHours(t *testing.T) {
+	tests := []struct {
+		name  string
+		hours models.WorkingHours
+		valid bool
+	}{
+		{"пустое расписание", models.WorkingHours{}, true},
+		{"обычный день", models.WorkingHours{"mon": {Open: "09:00", Close: "18:00"}}, true},
+		{"до полуночи", models.WorkingHours{"sat": {Open: "10:00", Close: "24:00"}}, true},
+		{"через полночь", models.WorkingHours{"fri": {Open: "22:00", Close: "02:00"}}, true},
+		{"неизвестный день", models.WorkingHours{"monday": {Open: "09:00", Close: "18:00"}}, false},
+		{"открытие в 24:00", models.WorkingHours{"mon": {Open: "24:00", Close: "02:00"}}, false},
+		{"без ведущего нуля", models.WorkingHours{"mon": {Open: "9:00", Close: "18:00"}}, false},
+		{"минуты вне диапазона", models.WorkingHours{"mon": {Open: "09:60", Close: "18:00"}}, false},
+		{"после 24:00", models.WorkingHours{"mon": {Open: "09:00", Close: "24:30"}}, false},
+		{"пустое время", models.WorkingHours{"mon": {Open: "09:00"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWorkingHours(tt.hours)
+			if tt.valid && err != nil {
+				t.Errorf("validateWorkingHours() = %v, want nil", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidWorkingHours) {
+				t.Errorf("validateWorkingHours() = %v, want ErrInvalidWorkingHours", err)
+			}
+		})
+	}
+}
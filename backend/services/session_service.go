@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"delivery-service/logging"
+	"delivery-service/models"
+	"delivery-service/repository"
+	"delivery-service/tracing"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const sessionActivityFlushInterval = 30 * time.Second
+
+// SessionActivity накапливает время последней активности сеансов в памяти
+// и периодически сохраняет его одним запросом, чтобы не делать UPDATE на
+// каждый авторизованный запрос
+type SessionActivity struct {
+	sessionRepo *repository.SessionRepository
+	interval    time.Duration
+
+	mu      sync.Mutex
+	pending map[int64]time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewSessionActivity(sessionRepo *repository.SessionRepository) *SessionActivity {
+	if sessionRepo == nil {
+		panic("session repository is required")
+	}
+	return &SessionActivity{
+		sessionRepo: sessionRepo,
+		interval:    sessionActivityFlushInterval,
+		pending:     make(map[int64]time.Time),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Touch отмечает активность сеанса, запись в БД произойдёт при следующем сбросе
+func (a *SessionActivity) Touch(sessionID int64) {
+	if sessionID <= 0 {
+		return
+	}
+	a.mu.Lock()
+	a.pending[sessionID] = time.Now()
+	a.mu.Unlock()
+}
+
+// LastSeen возвращает ещё не сохранённое время активности сеанса
+func (a *SessionActivity) LastSeen(sessionID int64) (time.Time, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	seen, ok := a.pending[sessionID]
+	return seen, ok
+}
+
+// Start запускает фоновый сброс накопленных данных. Ошибки пишутся
+// в логгер из ctx.
+func (a *SessionActivity) Start(ctx context.Context) {
+	go func() {
+		defer close(a.done)
+		ticker := time.NewTicker(a.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				a.flush(ctx)
+			case <-a.stop:
+				a.flush(ctx)
+				return
+			}
+		}
+	}()
+}
+
+// Stop останавливает фоновый сброс, сохранив накопленные данные
+func (a *SessionActivity) Stop() {
+	close(a.stop)
+	<-a.done
+}
+
+func (a *SessionActivity) flush(ctx context.Context) {
+	a.mu.Lock()
+	batch := a.pending
+	a.pending = make(map[int64]time.Time)
+	a.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	if err := a.sessionRepo.TouchSessions(ctx, batch); err != nil {
+		logging.FromContext(ctx).Error("Ошибка при сохранении активности сеансов", "error", err)
+		// возвращаем данные, чтобы попробовать ещё раз при следующем сбросе
+		a.mu.Lock()
+		for id, seen := range batch {
+			if current, ok := a.pending[id]; !ok || current.Before(seen) {
+				a.pending[id] = seen
+			}
+		}
+		a.mu.Unlock()
+	}
+}
+
+type SessionService struct {
+	sessionRepo *repository.SessionRepository
+	activity    *SessionActivity
+}
+
+func NewSessionService(sessionRepo *repository.SessionRepository, activity *SessionActivity) *SessionService {
+	if sessionRepo == nil || activity == nil {
+		panic("session repository and activity tracker are required")
+	}
+	return &SessionService{sessionRepo: sessionRepo, activity: activity}
+}
+
+// ListSessions возвращает активные сеансы пользователя, помечая текущий
+func (s *SessionService) ListSessions(ctx context.Context, userID, currentSessionID int64) ([]*models.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionService.ListSessions")
+	defer span.End()
+
+	sessions, err := s.sessionRepo.ListActiveSessions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении сеансов: %w", err)
+	}
+
+	for _, session := range sessions {
+		if seen, ok := s.activity.LastSeen(session.ID); ok && seen.After(session.LastSeenAt) {
+			session.LastSeenAt = seen
+		}
+		session.Current = session.ID == currentSessionID
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession завершает один из сеансов пользователя
+func (s *SessionService) RevokeSession(ctx context.Context, userID, sessionID int64) error {
+	ctx, span := tracing.Start(ctx, "SessionService.RevokeSession")
+	defer span.End()
+
+	if err := s.sessionRepo.RevokeSession(ctx, sessionID, userID, repository.RevokeReasonLogout); err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) || errors.Is(err, repository.ErrInvalidInput) {
+			return ErrSessionNotFound
+		}
+		return fmt.Errorf("ошибка при завершении сеанса: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+// секрет из RFC 6238, приложение B
+var rfcSecret = totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPCodeRFC6238(t *testing.T) {
+	key := []byte("12345678901234567890")
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		// последние шесть цифр восьмизначных кодов из RFC
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		if got := totpCode(key, tt.unix/totpPeriod); got != tt.want {
+			t.Errorf("totpCode(T=%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTOTPSkew(t *testing.T) {
+	key := []byte("12345678901234567890")
+	now := time.Unix(1111111109, 0)
+	current := now.Unix() / totpPeriod
+
+	tests := []struct {
+		name   string
+		offset time.Duration
+		ok     bool
+	}{
+		{"два шага назад", -2 * totpPeriod * time.Second, false},
+		{"шаг назад", -totpPeriod * time.Second, true},
+		{"текущий шаг", 0, true},
+		{"шаг вперёд", totpPeriod * time.Second, true},
+		{"два шага вперёд", 2 * totpPeriod * time.Second, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codeStep := now.Add(tt.offset).Unix() / totpPeriod
+			step, ok := validateTOTP(rfcSecret, totpCode(key, codeStep), now)
+			if ok != tt.ok {
+				t.Fatalf("validateTOTP ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && step != codeStep {
+				t.Errorf("step = %d, want %d", step, codeStep)
+			}
+			if ok && (step < current-totpSkew || step > current+totpSkew) {
+				t.Errorf("step %d outside skew window around %d", step, current)
+			}
+		})
+	}
+}
+
+func TestValidateTOTPRejectsMalformed(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+	}{
+		{"короткий код", rfcSecret, "08180"},
+		{"длинный код", rfcSecret, "0818040"},
+		{"неверный код", rfcSecret, "000000"},
+		{"пустой код", rfcSecret, ""},
+		{"битый секрет", "not base32!", "081804"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := validateTOTP(tt.secret, tt.code, now); ok {
+				t.Errorf("validateTOTP(%q, %q) accepted", tt.secret, tt.code)
+			}
+		})
+	}
+}
+
+// Повтор отсекается в БД условием last_used_step < step (см. UseStep),
+// поэтому код должен давать один и тот же шаг всё время, пока он действителен
+func TestValidateTOTPReplay(t *testing.T) {
+	key := []byte("12345678901234567890")
+	issued := time.Unix(1111111080, 0) // начало шага
+	code := totpCode(key, issued.Unix()/totpPeriod)
+
+	var lastUsed int64
+	use := func(at time.Time) bool {
+		step, ok := validateTOTP(rfcSecret, code, at)
+		if !ok || step <= lastUsed {
+			return false
+		}
+		lastUsed = step
+		return true
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		ok   bool
+	}{
+		{"первый ввод", issued.Add(5 * time.Second), true},
+		{"повтор в том же шаге", issued.Add(20 * time.Second), false},
+		{"повтор в следующем шаге", issued.Add(40 * time.Second), false},
+		{"повтор после окна", issued.Add(90 * time.Second), false},
+	}
+	for _, tt := range tests {
+		if got := use(tt.at); got != tt.ok {
+			t.Errorf("%s: accepted = %v, want %v", tt.name, got, tt.ok)
+		}
+	}
+
+	// следующий код принимается, хотя предыдущий уже использован
+	next := totpCode(key, issued.Unix()/totpPeriod+1)
+	step, ok := validateTOTP(rfcSecret, next, issued.Add(35*time.Second))
+	if !ok || step <= lastUsed {
+		t.Errorf("next code rejected: step %d, last used %d", step, lastUsed)
+	}
+}
+
+func TestNormalizeTwoFactorCode(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{" 123 456 ", "123456"},
+		{"abcde-fghjk", "ABCDEFGHJK"},
+		{"ABCDE FGHJK", "ABCDEFGHJK"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeTwoFactorCode(tt.in); got != tt.want {
+			t.Errorf("normalizeTwoFactorCode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
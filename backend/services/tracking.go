@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"delivery-service/models"
+	"delivery-service/repository"
+	"delivery-service/tracing"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidTrackingCode = errors.New("некорректный трек-номер")
+
+const (
+	trackingCodePrefix = "DS"
+	trackingCodeLength = 12
+	// алфавит Crockford base32: без I, L, O, U, чтобы код было проще продиктовать
+	trackingCodeAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// generateTrackingCode возвращает случайный код вида DSXXXXXXXXXXXX (60 бит энтропии)
+func generateTrackingCode() (string, error) {
+	buf := make([]byte, trackingCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	code := make([]byte, trackingCodeLength)
+	for i, b := range buf {
+		// 256 кратно 32, поэтому распределение остаётся равномерным
+		code[i] = trackingCodeAlphabet[int(b)%len(trackingCodeAlphabet)]
+	}
+
+	return trackingCodePrefix + string(code), nil
+}
+
+// normalizeTrackingCode приводит введённый пользователем код к каноничному виду
+func normalizeTrackingCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+
+	if len(code) != len(trackingCodePrefix)+trackingCodeLength || !strings.HasPrefix(code, trackingCodePrefix) {
+		return "", ErrInvalidTrackingCode
+	}
+	for _, c := range code[len(trackingCodePrefix):] {
+		if !strings.ContainsRune(trackingCodeAlphabet, c) {
+			return "", ErrInvalidTrackingCode
+		}
+	}
+
+	return code, nil
+}
+
+// TrackOrder возвращает публичную историю заказа по трек-номеру
+func (s *OrderService) TrackOrder(ctx context.Context, code string) (*models.TrackingInfo, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.TrackOrder")
+	defer span.End()
+
+	code, err := normalizeTrackingCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.orderRepo.GetOrderByTrackingCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, repository.ErrOrderNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("ошибка при поиске заказа: %w", err)
+	}
+
+	events, err := s.orderRepo.GetStatusEvents(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении истории заказа: %w", err)
+	}
+
+	info := &models.TrackingInfo{
+		TrackingCode: order.TrackingCode,
+		Status:       order.Status,
+		DeliveryDate: order.DeliveryDetails.Date,
+		CreatedAt:    order.CreatedAt,
+		History:      make([]models.TrackingEvent, 0, len(events)),
+	}
+	for _, item := range order.Items {
+		info.ItemsCount += item.Quantity
+	}
+	// комментарии и исполнители не публикуются: там могут быть персональные данные
+	for _, event := range events {
+		info.History = append(info.History, models.TrackingEvent{
+			Status:    event.ToStatus,
+			Location:  event.Location,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+
+	return info, nil
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeTrackingCode(t *testing.T) {
+	tests := []struct {
+		name, code, want string
+		wantErr          bool
+	}{
+		{"каноничный вид", "DS0123456789AB", "DS0123456789AB", false},
+		{"строчные буквы и пробелы", "  ds0123456789ab ", "DS0123456789AB", false},
+		{"дефисы и пробелы внутри", "DS-0123 4567-89AB", "DS0123456789AB", false},
+		{"пустой код", "", "", true},
+		{"короткий код", "DS0123456789A", "", true},
+		{"длинный код", "DS0123456789ABC", "", true},
+		{"другой префикс", "XX0123456789AB", "", true},
+		// I, L, O и U не входят в алфавит, их не путают с 1 и 0
+		{"буква вне алфавита", "DS0123456789AO", "", true},
+		{"кириллица", "DS0123456789АВ", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeTrackingCode(tt.code)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidTrackingCode) {
+					t.Errorf("normalizeTrackingCode(%q) error = %v, want ErrInvalidTrackingCode", tt.code, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("normalizeTrackingCode(%q) = %q, %v, want %q", tt.code, got, err, tt.want)
+			}
+		})
+	}
+}
+
+// сгенерированный код проходит нормализацию без изменений
+func TestGenerateTrackingCode(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		code, err := generateTrackingCode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if normalized, err := normalizeTrackingCode(code); err != nil || normalized != code {
+			t.Fatalf("generated code %q is not canonical: %q, %v", code, normalized, err)
+		}
+		if seen[code] {
+			t.Fatalf("duplicate code %q", code)
+		}
+		seen[code] = true
+	}
+}
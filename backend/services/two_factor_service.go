@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"delivery-service/config"
+	"delivery-service/models"
+	"delivery-service/repository"
+	"delivery-service/tracing"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("двухфакторная аутентификация уже включена")
+	ErrTwoFactorNotEnabled     = errors.New("двухфакторная аутентификация не включена")
+	ErrTwoFactorSetupRequired  = errors.New("сначала начните подключение двухфакторной аутентификации")
+	ErrInvalidTwoFactorCode    = errors.New("неверный код подтверждения")
+)
+
+type TwoFactorService struct {
+	userRepo      *repository.UserRepository
+	twoFactorRepo *repository.TwoFactorRepository
+	auditRepo     *repository.AuditRepository
+	issuer        string
+}
+
+func NewTwoFactorService(
+	cfg *config.Config,
+	userRepo *repository.UserRepository,
+	twoFactorRepo *repository.TwoFactorRepository,
+	auditRepo *repository.AuditRepository,
+) *TwoFactorService {
+	if userRepo == nil || twoFactorRepo == nil || auditRepo == nil {
+		panic("two-factor service dependencies are required")
+	}
+
+	return &TwoFactorService{
+		userRepo:      userRepo,
+		twoFactorRepo: twoFactorRepo,
+		auditRepo:     auditRepo,
+		issuer:        cfg.Auth.TOTPIssuer,
+	}
+}
+
+// Setup выпускает новый секрет. 2FA включится только после Confirm.
+func (s *TwoFactorService) Setup(ctx context.Context, userID int64) (*models.TwoFactorSetupResponse, error) {
+	ctx, span := tracing.Start(ctx, "TwoFactorService.Setup")
+	defer span.End()
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении пользователя: %w", err)
+	}
+	if user.TwoFactorEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при генерации секрета: %w", err)
+	}
+
+	if err := s.twoFactorRepo.SaveSecret(ctx, userID, secret); err != nil {
+		if errors.Is(err, repository.ErrTwoFactorEnabled) {
+			return nil, ErrTwoFactorAlreadyEnabled
+		}
+		return nil, fmt.Errorf("ошибка при сохранении секрета: %w", err)
+	}
+
+	return &models.TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURI: totpURI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm включает 2FA, если пользователь ввёл верный код из приложения,
+// и возвращает коды восстановления. Они показываются только один раз.
+func (s *TwoFactorService) Confirm(ctx context.Context, userID int64, code string, client models.ClientInfo) (*models.TwoFactorRecoveryCodes, error) {
+	_, span := tracing.Start(ctx, "TwoFactorService.Confirm")
+	defer span.End()
+
+	secret, err := s.twoFactorRepo.GetSecret(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTwoFactorNotFound) {
+			return nil, ErrTwoFactorSetupRequired
+		}
+		return nil, fmt.Errorf("ошибка при получении секрета: %w", err)
+	}
+	if secret.ConfirmedAt != nil {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := validateTOTP(secret.Secret, normalizeTwoFactorCode(code), time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при генерации кодов восстановления: %w", err)
+	}
+
+	if err := s.twoFactorRepo.Enable(ctx, userID, step, hashes); err != nil {
+		if errors.Is(err, repository.ErrTwoFactorEnabled) {
+			return nil, ErrTwoFactorAlreadyEnabled
+		}
+		return nil, fmt.Errorf("ошибка при включении двухфакторной аутентификации: %w", err)
+	}
+
+	recordAudit(ctx, s.auditRepo, userID, models.AuditTwoFactorOn, client)
+	return &models.TwoFactorRecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+// Disable отключает 2FA. Нужны пароль и действующий код (или код восстановления).
+func (s *TwoFactorService) Disable(ctx context.Context, userID int64, req *models.TwoFactorDisableRequest, client models.ClientInfo) error {
+	ctx, span := tracing.Start(ctx, "TwoFactorService.Disable")
+	defer span.End()
+
+	if req == nil {
+		return ErrInvalidInput
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("ошибка при получении пользователя: %w", err)
+	}
+	if err := comparePassword(ctx, []byte(user.PasswordHash), req.Password); err != nil {
+		return ErrWrongCurrentPassword
+	}
+
+	if err := s.VerifyCode(ctx, userID, req.Code); err != nil {
+		return err
+	}
+
+	if err := s.twoFactorRepo.Disable(ctx, userID); err != nil {
+		return fmt.Errorf("ошибка при отключении двухфакторной аутентификации: %w", err)
+	}
+
+	recordAudit(ctx, s.auditRepo, userID, models.AuditTwoFactorOff, client)
+	return nil
+}
+
+// VerifyCode проверяет код из приложения или одноразовый код восстановления.
+// Принятый код повторно использовать нельзя.
+func (s *TwoFactorService) VerifyCode(ctx context.Context, userID int64, code string) error {
+	_, span := tracing.Start(ctx, "TwoFactorService.VerifyCode")
+	defer span.End()
+
+	secret, err := s.twoFactorRepo.GetSecret(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTwoFactorNotFound) {
+			return ErrTwoFactorNotEnabled
+		}
+		return fmt.Errorf("ошибка при получении секрета: %w", err)
+	}
+	if secret.ConfirmedAt == nil {
+		return ErrTwoFactorNotEnabled
+	}
+
+	code = normalizeTwoFactorCode(code)
+	if code == "" {
+		return ErrInvalidTwoFactorCode
+	}
+
+	if len(code) == totpDigits {
+		step, ok := validateTOTP(secret.Secret, code, time.Now())
+		if !ok {
+			return ErrInvalidTwoFactorCode
+		}
+		if err := s.twoFactorRepo.UseStep(ctx, userID, step); err != nil {
+			if errors.Is(err, repository.ErrTOTPStepUsed) {
+				return ErrInvalidTwoFactorCode
+			}
+			return fmt.Errorf("ошибка при проверке кода: %w", err)
+		}
+		return nil
+	}
+
+	if len(code) != recoveryCodeLength {
+		return ErrInvalidTwoFactorCode
+	}
+	if err := s.twoFactorRepo.UseRecoveryCode(ctx, userID, hashToken(code)); err != nil {
+		if errors.Is(err, repository.ErrRecoveryCodeInvalid) {
+			return ErrInvalidTwoFactorCode
+		}
+		return fmt.Errorf("ошибка при проверке кода восстановления: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"delivery-service/models"
+	"delivery-service/validation"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeUpdateUserRequest(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	req := &models.UpdateUserRequest{
+		Name:       str("  Иван Петров "),
+		Phone:      str(" +7 (999) 123-45-67 "),
+		WhatsApp:   str("+1 555-123-4567"),
+		PostalCode: str(" sw1a 1aa "),
+		Country:    str(" UK "),
+		Telegram:   str("@ivan_petrov\n"),
+	}
+	normalizeUpdateUserRequest(req)
+
+	want := &models.UpdateUserRequest{
+		Name:       str("Иван Петров"),
+		Phone:      str("+79991234567"),
+		WhatsApp:   str("+15551234567"),
+		PostalCode: str("SW1A 1AA"),
+		Country:    str("UK"),
+		Telegram:   str("@ivan_petrov"),
+	}
+	if !reflect.DeepEqual(req, want) {
+		t.Errorf("normalizeUpdateUserRequest() = %+v, want %+v", req, want)
+	}
+	// сохраняется то же значение, что прошло проверку
+	if err := validateUpdateUserRequest(req); err != nil {
+		t.Errorf("normalized request is invalid: %v", err)
+	}
+
+	normalizeUpdateUserRequest(nil)
+}
+
+func TestValidateUpdateUserRequest(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name string
+		req  *models.UpdateUserRequest
+		want []string
+	}{
+		{"пустой запрос", &models.UpdateUserRequest{}, nil},
+		{"имя нельзя очистить", &models.UpdateUserRequest{Name: str("")}, []string{"name"}},
+		{"телефон можно очистить", &models.UpdateUserRequest{Phone: str("")}, nil},
+		{"индекс по стране", &models.UpdateUserRequest{Country: str("Россия"), PostalCode: str("1010")}, []string{"postal_code"}},
+		{"способ связи", &models.UpdateUserRequest{PreferredContact: str("pigeon")}, []string{"preferred_contact"}},
+		{
+			"все ошибки сразу",
+			&models.UpdateUserRequest{Phone: str("123"), Telegram: str("@x"), Language: str("xx")},
+			[]string{"phone", "telegram", "language"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateUpdateUserRequest(tt.req)
+			var errs validation.Errors
+			if err != nil && !errors.As(err, &errs) {
+				t.Fatalf("validateUpdateUserRequest() = %v", err)
+			}
+			var fields []string
+			for _, e := range errs {
+				fields = append(fields, e.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.want) {
+				t.Errorf("invalid fields = %v, want %v", fields, tt.want)
+			}
+		})
+	}
+
+	if err := validateUpdateUserRequest(nil); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("validateUpdateUserRequest(nil) = %v, want ErrInvalidInput", err)
+	}
+}
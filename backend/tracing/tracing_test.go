@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+	"delivery-service/config"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetup(t *testing.T) {
+	shutdown, err := Setup(config.TracingConfig{Exporter: config.TracingNone})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown() = %v", err)
+	}
+
+	if _, err := Setup(config.TracingConfig{Exporter: "jaeger"}); err == nil {
+		t.Error("Setup() with unknown exporter succeeded")
+	}
+}
+
+func TestEnd(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	_, ok := tracer.Start(context.Background(), "ok")
+	End(ok, nil)
+	_, failed := tracer.Start(context.Background(), "failed")
+	End(failed, errors.New("connection reset"))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	if status := spans[0].Status(); status.Code != codes.Unset {
+		t.Errorf("successful span status = %v", status)
+	}
+	if status := spans[1].Status(); status.Code != codes.Error || status.Description != "connection reset" {
+		t.Errorf("failed span status = %v", status)
+	}
+	// ошибка записывается событием exception
+	if events := spans[1].Events(); len(events) != 1 || events[0].Name != "exception" {
+		t.Errorf("failed span events = %v", events)
+	}
+}
@@ -0,0 +1,84 @@
+package validation
+
+import (
+	"regexp"
+	"strings"
+)
+
+// форматы индексов по коду страны ISO 3166-1 alpha-2
+var postalCodePatterns = map[string]*regexp.Regexp{
+	"RU": regexp.MustCompile(`^\d{6}$`),
+	"BY": regexp.MustCompile(`^\d{6}$`),
+	"KZ": regexp.MustCompile(`^(\d{6}|[A-Z]\d{2}[A-Z]\d[A-Z]\d)$`),
+	"UZ": regexp.MustCompile(`^\d{6}$`),
+	"KG": regexp.MustCompile(`^\d{6}$`),
+	"TJ": regexp.MustCompile(`^\d{6}$`),
+	"AM": regexp.MustCompile(`^\d{4}$`),
+	"GE": regexp.MustCompile(`^\d{4}$`),
+	"AZ": regexp.MustCompile(`^(AZ ?)?\d{4}$`),
+	"MD": regexp.MustCompile(`^(MD-?)?\d{4}$`),
+	"UA": regexp.MustCompile(`^\d{5}$`),
+	"CN": regexp.MustCompile(`^\d{6}$`),
+	"TR": regexp.MustCompile(`^\d{5}$`),
+	"DE": regexp.MustCompile(`^\d{5}$`),
+	"FR": regexp.MustCompile(`^\d{5}$`),
+	"IT": regexp.MustCompile(`^\d{5}$`),
+	"ES": regexp.MustCompile(`^\d{5}$`),
+	"PL": regexp.MustCompile(`^\d{2}-\d{3}$`),
+	"US": regexp.MustCompile(`^\d{5}(-\d{4})?$`),
+	"CA": regexp.MustCompile(`^[A-Z]\d[A-Z] ?\d[A-Z]\d$`),
+	"GB": regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`),
+}
+
+// для стран, формат индекса которых неизвестен
+var genericPostalCode = regexp.MustCompile(`^[A-Z0-9][A-Z0-9 -]{1,9}$`)
+
+// страна в профиле вводится свободным текстом
+var countryAliases = map[string]string{
+	"россия": "RU", "российская федерация": "RU", "russia": "RU", "russian federation": "RU",
+	"беларусь": "BY", "белоруссия": "BY", "belarus": "BY",
+	"казахстан": "KZ", "kazakhstan": "KZ",
+	"узбекистан": "UZ", "uzbekistan": "UZ",
+	"кыргызстан": "KG", "киргизия": "KG", "kyrgyzstan": "KG",
+	"таджикистан": "TJ", "tajikistan": "TJ",
+	"армения": "AM", "armenia": "AM",
+	"грузия": "GE", "georgia": "GE",
+	"азербайджан": "AZ", "azerbaijan": "AZ",
+	"молдова": "MD", "молдавия": "MD", "moldova": "MD",
+	"украина": "UA", "ukraine": "UA",
+	"китай": "CN", "china": "CN",
+	"турция": "TR", "turkey": "TR", "türkiye": "TR",
+	"германия": "DE", "germany": "DE",
+	"франция": "FR", "france": "FR",
+	"италия": "IT", "italy": "IT",
+	"испания": "ES", "spain": "ES",
+	"польша": "PL", "poland": "PL",
+	"сша": "US", "usa": "US", "united states": "US",
+	"канада": "CA", "canada": "CA",
+	"великобритания": "GB", "united kingdom": "GB", "uk": "GB",
+}
+
+// PostalCode проверяет индекс по формату страны country (код ISO или
+// название). Для неизвестной страны проверяется только общий вид индекса.
+func PostalCode(country *string) Rule {
+	pattern := genericPostalCode
+	if country != nil {
+		if p, ok := postalCodePatterns[countryCode(*country)]; ok {
+			pattern = p
+		}
+	}
+	return func(value string) string {
+		if value == "" || pattern.MatchString(strings.ToUpper(value)) {
+			return ""
+		}
+		return "invalid_postal_code"
+	}
+}
+
+func countryCode(country string) string {
+	country = strings.ToLower(strings.TrimSpace(country))
+	if code, ok := countryAliases[country]; ok {
+		return code
+	}
+	return strings.ToUpper(country)
+}
@@ -0,0 +1,133 @@
+package validation
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	e164Regex     = regexp.MustCompile(`^\+[1-9]\d{7,14}$`)
+	telegramRegex = regexp.MustCompile(`^@?[A-Za-z][A-Za-z0-9_]{4,31}$`)
+
+	// разделители, которые люди ставят в номерах телефонов: +7 (999) 123-45-67
+	phoneSeparators = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "")
+
+	earliestBirthDate = time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// NotEmpty запрещает очищать поле
+func NotEmpty(value string) string {
+	if value == "" {
+		return "required"
+	}
+	return ""
+}
+
+// Name - имя от 2 до 100 символов: буквы, пробелы, дефисы, апострофы и точки
+func Name(value string) string {
+	if value == "" {
+		return ""
+	}
+	if n := utf8.RuneCountInString(value); n < 2 || n > 100 {
+		return "invalid_name"
+	}
+	for _, r := range value {
+		if !unicode.IsLetter(r) && !strings.ContainsRune(" -'’.", r) {
+			return "invalid_name"
+		}
+	}
+	return ""
+}
+
+// Phone - номер в формате E.164. Пробелы, дефисы и скобки допускаются.
+func Phone(value string) string {
+	if value == "" || isE164(value) {
+		return ""
+	}
+	return "invalid_phone"
+}
+
+// WhatsApp - номер WhatsApp, те же требования, что и к телефону
+func WhatsApp(value string) string {
+	if value == "" || isE164(value) {
+		return ""
+	}
+	return "invalid_whatsapp"
+}
+
+// Telegram - имя пользователя Telegram, с @ или без: 5-32 символа,
+// латинские буквы, цифры и подчёркивание, начинается с буквы
+func Telegram(value string) string {
+	if value == "" || telegramRegex.MatchString(value) {
+		return ""
+	}
+	return "invalid_telegram"
+}
+
+// Language - двухбуквенный код языка ISO 639-1
+func Language(value string) string {
+	if value == "" || isoLanguages[value] {
+		return ""
+	}
+	return "invalid_language"
+}
+
+// BirthDate - дата в формате YYYY-MM-DD не раньше 1900 года и не в будущем
+func BirthDate(value string) string {
+	if value == "" {
+		return ""
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil || date.Before(earliestBirthDate) {
+		return "invalid_birth_date"
+	}
+	if date.After(time.Now()) {
+		return "birth_date_in_future"
+	}
+	return ""
+}
+
+// OneOf допускает только перечисленные значения
+func OneOf(code string, allowed ...string) Rule {
+	return func(value string) string {
+		if value == "" {
+			return ""
+		}
+		for _, a := range allowed {
+			if value == a {
+				return ""
+			}
+		}
+		return code
+	}
+}
+
+// NormalizePhone убирает разделители, допустимые в Phone и WhatsApp:
+// +7 (999) 123-45-67 сохраняется как +79991234567
+func NormalizePhone(value string) string {
+	return phoneSeparators.Replace(value)
+}
+
+func isE164(value string) bool {
+	return e164Regex.MatchString(NormalizePhone(value))
+}
+
+// коды ISO 639-1
+var isoLanguages = func() map[string]bool {
+	codes := strings.Fields(`
+		aa ab ae af ak am an ar as av ay az ba be bg bi bm bn bo br bs ca ce ch co cr cs cu cv cy
+		da de dv dz ee el en eo es et eu fa ff fi fj fo fr fy ga gd gl gn gu gv ha he hi ho hr ht
+		hu hy hz ia id ie ig ii ik io is it iu ja jv ka kg ki kj kk kl km kn ko kr ks ku kv kw ky
+		la lb lg li ln lo lt lu lv mg mh mi mk ml mn mr ms mt my na nb nd ne ng nl nn no nr nv ny
+		oc oj om or os pa pi pl ps pt qu rm rn ro ru rw sa sc sd se sg si sk sl sm sn so sq sr ss
+		st su sv sw ta te tg th ti tk tl tn to tr ts tt tw ty ug uk ur uz ve vi vo wa wo xh yi yo
+		za zh zu`)
+	set := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		set[code] = true
+	}
+	return set
+}()
@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"strings"
+)
+
+// Rule проверяет значение поля и возвращает код ошибки или "", если значение
+// корректно. Коды совпадают с ключами сообщений в каталоге i18n.
+// Пустая строка означает, что поле очищается, поэтому правила её пропускают;
+// запретить пустое значение можно правилом NotEmpty.
+type Rule func(value string) string
+
+// FieldError - ошибка одного поля запроса
+type FieldError struct {
+	Field string
+	Code  string
+}
+
+// Errors - все ошибки запроса. Проверка не останавливается на первом поле,
+// чтобы клиент мог показать все ошибки сразу.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, len(e))
+	for i, f := range e {
+		parts[i] = f.Field + ": " + f.Code
+	}
+	return "validation failed: " + strings.Join(parts, ", ")
+}
+
+// FieldRules описывает проверку одного поля, см. Field
+type FieldRules struct {
+	name  string
+	value *string
+	rules []Rule
+}
+
+// Field описывает поле запроса и правила для него. Поле, которого нет
+// в запросе (nil), не проверяется.
+func Field(name string, value *string, rules ...Rule) FieldRules {
+	return FieldRules{name: name, value: value, rules: rules}
+}
+
+// Validate проверяет поля и возвращает Errors или nil. Для каждого поля
+// возвращается только первая ошибка.
+func Validate(fields ...FieldRules) error {
+	var errs Errors
+	for _, f := range fields {
+		if f.value == nil {
+			continue
+		}
+		value := strings.TrimSpace(*f.value)
+		for _, rule := range f.rules {
+			if code := rule(value); code != "" {
+				errs = append(errs, FieldError{Field: f.name, Code: code})
+				break
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
@@ -0,0 +1,162 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRules(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	gender := OneOf("invalid_gender", "male", "female")
+
+	tests := []struct {
+		name  string
+		rule  Rule
+		value string
+		want  string
+	}{
+		{"NotEmpty: пусто", NotEmpty, "", "required"},
+		{"NotEmpty: значение", NotEmpty, "x", ""},
+
+		{"Name: пусто", Name, "", ""},
+		{"Name: кириллица", Name, "Анна-Мария", ""},
+		{"Name: апостроф и точка", Name, "O’Neil Jr.", ""},
+		{"Name: одна буква", Name, "A", "invalid_name"},
+		{"Name: 100 символов", Name, strings.Repeat("я", 100), ""},
+		{"Name: 101 символ", Name, strings.Repeat("я", 101), "invalid_name"},
+		{"Name: цифры", Name, "R2D2", "invalid_name"},
+
+		{"Phone: пусто", Phone, "", ""},
+		{"Phone: E.164", Phone, "+79991234567", ""},
+		{"Phone: с разделителями", Phone, "+7 (999) 123-45-67", ""},
+		{"Phone: без плюса", Phone, "89991234567", "invalid_phone"},
+		{"Phone: ведущий ноль", Phone, "+0123456789", "invalid_phone"},
+		{"Phone: слишком короткий", Phone, "+1234567", "invalid_phone"},
+		{"Phone: слишком длинный", Phone, "+1234567890123456", "invalid_phone"},
+		{"Phone: буквы", Phone, "+7999CALLME", "invalid_phone"},
+		{"WhatsApp: номер", WhatsApp, "+1 555 123 4567", ""},
+		{"WhatsApp: свой код ошибки", WhatsApp, "12345", "invalid_whatsapp"},
+
+		{"Telegram: пусто", Telegram, "", ""},
+		{"Telegram: с @", Telegram, "@ivan_petrov", ""},
+		{"Telegram: без @", Telegram, "ivan_petrov", ""},
+		{"Telegram: короткий", Telegram, "@ivan", "invalid_telegram"},
+		{"Telegram: начинается с цифры", Telegram, "1ivan_petrov", "invalid_telegram"},
+		{"Telegram: кириллица", Telegram, "иван_петров", "invalid_telegram"},
+		{"Telegram: 32 символа", Telegram, "a" + strings.Repeat("b", 31), ""},
+		{"Telegram: 33 символа", Telegram, "a" + strings.Repeat("b", 32), "invalid_telegram"},
+
+		{"Language: ru", Language, "ru", ""},
+		{"Language: неизвестный код", Language, "xx", "invalid_language"},
+		{"Language: верхний регистр", Language, "RU", "invalid_language"},
+		{"Language: с регионом", Language, "en-US", "invalid_language"},
+
+		{"BirthDate: пусто", BirthDate, "", ""},
+		{"BirthDate: дата", BirthDate, "1990-05-17", ""},
+		{"BirthDate: 1900-01-01", BirthDate, "1900-01-01", ""},
+		{"BirthDate: раньше 1900", BirthDate, "1899-12-31", "invalid_birth_date"},
+		{"BirthDate: формат", BirthDate, "17.05.1990", "invalid_birth_date"},
+		{"BirthDate: несуществующий день", BirthDate, "2001-02-29", "invalid_birth_date"},
+		{"BirthDate: в будущем", BirthDate, tomorrow, "birth_date_in_future"},
+
+		{"OneOf: пусто", gender, "", ""},
+		{"OneOf: допустимое", gender, "female", ""},
+		{"OneOf: регистр важен", gender, "Female", "invalid_gender"},
+		{"OneOf: недопустимое", gender, "other", "invalid_gender"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule(tt.value); got != tt.want {
+				t.Errorf("rule(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostalCode(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		country *string
+		value   string
+		want    string
+	}{
+		{"RU по коду", str("RU"), "101000", ""},
+		{"RU по названию", str(" Россия "), "101000", ""},
+		{"RU по английскому названию", str("Russian Federation"), "10100", "invalid_postal_code"},
+		{"код в нижнем регистре", str("de"), "10115", ""},
+		{"US ZIP+4", str("США"), "12345-6789", ""},
+		{"GB", str("uk"), "SW1A 1AA", ""},
+		{"GB в нижнем регистре", str("United Kingdom"), "sw1a 1aa", ""},
+		{"CA", str("canada"), "K1A 0B1", ""},
+		{"KZ новый формат", str("Казахстан"), "A15C9T5", ""},
+		{"PL без дефиса", str("Польша"), "00950", "invalid_postal_code"},
+		{"пустой индекс", str("RU"), "", ""},
+		{"неизвестная страна", str("Бразилия"), "01310-100", ""},
+		{"неизвестная страна, мусор", str("Бразилия"), "№1", "invalid_postal_code"},
+		{"страна не указана", nil, "ABC 123", ""},
+		{"страна не указана, слишком длинный", nil, "12345678901", "invalid_postal_code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PostalCode(tt.country)(tt.value); got != tt.want {
+				t.Errorf("PostalCode(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	err := Validate(
+		Field("first_name", str("  Иван  "), NotEmpty, Name),
+		Field("last_name", str("   "), NotEmpty, Name),
+		Field("phone", str("12"), NotEmpty, Phone),
+		Field("telegram", nil, NotEmpty, Telegram),
+		Field("language", str("xx"), Language),
+	)
+
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Validate() = %v, want Errors", err)
+	}
+	// пробелы обрезаются, для поля возвращается только первая ошибка,
+	// отсутствующее поле не проверяется
+	want := Errors{
+		{Field: "last_name", Code: "required"},
+		{Field: "phone", Code: "invalid_phone"},
+		{Field: "language", Code: "invalid_language"},
+	}
+	if !reflect.DeepEqual(errs, want) {
+		t.Errorf("Validate() = %+v, want %+v", errs, want)
+	}
+	if got := errs.Error(); got != "validation failed: last_name: required, phone: invalid_phone, language: invalid_language" {
+		t.Errorf("Error() = %q", got)
+	}
+
+	if err := Validate(Field("phone", str("+79991234567"), Phone), Field("name", nil, NotEmpty)); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"+7 (999) 123-45-67", "+79991234567"},
+		{"+79991234567", "+79991234567"},
+		{"", ""},
+		// нормализация не проверяет номер
+		{"call me", "callme"},
+	}
+	for _, tt := range tests {
+		if got := NormalizePhone(tt.value); got != tt.want {
+			t.Errorf("NormalizePhone(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
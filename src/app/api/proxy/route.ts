@@ -1,6 +1,107 @@
 import { NextRequest, NextResponse } from 'next/server';
 
-const API_BASE_URL = 'http://92.246.76.171:8080/api';
+// Адрес API задаётся явно. Бэкенд доверяет X-Forwarded-For только от адресов
+// из TRUSTED_PROXIES: если прокси обращается к нему с другого адреса, все
+// клиенты прокси получат один IP и общие лимиты. Прокси, запущенный рядом
+// с бэкендом, лучше направлять на 127.0.0.1, иначе его адрес или подсеть
+// нужно добавить в TRUSTED_PROXIES.
+const API_BASE_URL = process.env.BACKEND_API_URL;
+
+function backendUrl(path: string): string {
+  if (!API_BASE_URL) {
+    throw new Error('BACKEND_API_URL is not set');
+  }
+  return `${API_BASE_URL}/${path}`;
+}
+
+// W3C trace-context: https://www.w3.org/TR/trace-context/
+const TRACEPARENT_RE = /^00-([0-9a-f]{32})-([0-9a-f]{16})-([0-9a-f]{2})$/;
+const TRACE_HEADERS = ['traceparent', 'tracestate'];
+// Заголовки с адресом клиента не копируются: их может подделать сам клиент
+const FORWARDING_HEADERS = ['x-forwarded-for', 'x-real-ip', 'forwarded'];
+const TRACE_SAMPLE_RATIO = Number(process.env.TRACING_SAMPLE_RATIO ?? '1');
+
+function randomHex(bytes: number): string {
+  const buf = new Uint8Array(bytes);
+  crypto.getRandomValues(buf);
+  return Array.from(buf, (b) => b.toString(16).padStart(2, '0')).join('');
+}
+
+// Продолжает трассу браузера или начинает новую, чтобы запросы к бэкенду
+// через прокси были связаны в одну трассу. Некорректные заголовки не передаются.
+function traceHeaders(request: NextRequest): Record<string, string> {
+  const match = TRACEPARENT_RE.exec(request.headers.get('traceparent') ?? '');
+  if (match && !/^0+$/.test(match[1]) && !/^0+$/.test(match[2])) {
+    const headers: Record<string, string> = {
+      traceparent: `00-${match[1]}-${randomHex(8)}-${match[3]}`,
+    };
+    const tracestate = request.headers.get('tracestate');
+    if (tracestate) {
+      headers.tracestate = tracestate;
+    }
+    return headers;
+  }
+
+  const sampled = Math.random() < TRACE_SAMPLE_RATIO ? '01' : '00';
+  return { traceparent: `00-${randomHex(16)}-${randomHex(8)}-${sampled}` };
+}
+
+// Адрес клиента добавляет последним в X-Forwarded-For платформа (Vercel) или
+// обратный прокси перед Next.js, предыдущие элементы мог прислать сам клиент.
+// Бэкенду передаётся только этот адрес.
+function forwardedFor(request: NextRequest): Record<string, string> {
+  const hops = (request.headers.get('x-forwarded-for') ?? '')
+    .split(',')
+    .map((hop) => hop.trim())
+    .filter(Boolean);
+  const client = hops[hops.length - 1] ?? request.headers.get('x-real-ip')?.trim();
+  return client ? { 'x-forwarded-for': client } : {};
+}
+
+// Заголовки соединения с бэкендом. fetch уже распаковал тело,
+// поэтому content-encoding и content-length ему больше не соответствуют.
+const HOP_BY_HOP_HEADERS = ['connection', 'keep-alive', 'transfer-encoding', 'content-encoding', 'content-length'];
+
+// Ответ бэкенда передаётся клиенту без изменений: статус, заголовки
+// (Content-Type problem+json, Content-Language, Retry-After, RateLimit-*)
+// и тело, в том числе пустое у 202 и 204
+function passThrough(response: Response): NextResponse {
+  const headers = new Headers(response.headers);
+  HOP_BY_HOP_HEADERS.forEach((name) => headers.delete(name));
+  if (!headers.has('cache-control')) {
+    headers.set('cache-control', 'no-store, max-age=0');
+  }
+  const body = response.status === 204 || response.status === 304 ? null : response.body;
+  return new NextResponse(body, {
+    status: response.status,
+    statusText: response.statusText,
+    headers,
+  });
+}
+
+// Ошибка самого прокси в том же формате RFC 7807, что и ошибки бэкенда,
+// чтобы readApiError и ветвление по code работали одинаково
+function proxyProblem(request: NextRequest, error: unknown): NextResponse {
+  console.error('Proxy error:', error);
+  return NextResponse.json(
+    {
+      type: 'about:blank',
+      title: 'Bad Gateway',
+      status: 502,
+      detail: 'Сервер временно недоступен, попробуйте позже',
+      instance: new URL(request.url).pathname,
+      code: 'proxy_unavailable',
+    },
+    {
+      status: 502,
+      headers: {
+        'Content-Type': 'application/problem+json',
+        'Content-Language': 'ru',
+        'Cache-Control': 'no-store, max-age=0',
+      },
+    }
+  );
+}
 
 export async function GET(request: NextRequest) {
   const { searchParams } = new URL(request.url);
@@ -12,31 +113,21 @@ export async function GET(request: NextRequest) {
     // Копируем заголовки из запроса
     request.headers.forEach((value, key) => {
       // Исключаем некоторые заголовки, которые могут вызвать проблемы
-      if (!['host', 'connection', 'content-length'].includes(key.toLowerCase())) {
+      if (!['host', 'connection', 'content-length', ...FORWARDING_HEADERS, ...TRACE_HEADERS].includes(key.toLowerCase())) {
         headers[key] = value;
       }
     });
+    Object.assign(headers, traceHeaders(request), forwardedFor(request));
     
-    const response = await fetch(`${API_BASE_URL}/${path}`, {
+    const response = await fetch(backendUrl(path), {
       method: 'GET',
       headers,
       credentials: 'include',
     });
     
-    const data = await response.json();
-    
-    return NextResponse.json(data, {
-      status: response.status,
-      headers: {
-        'Cache-Control': 'no-store, max-age=0',
-      },
-    });
+    return passThrough(response);
   } catch (error) {
-    console.error('Proxy error:', error);
-    return NextResponse.json(
-      { error: 'Ошибка при выполнении запроса' },
-      { status: 500 }
-    );
+    return proxyProblem(request, error);
   }
 }
 
@@ -45,43 +136,34 @@ export async function POST(request: NextRequest) {
   const path = searchParams.get('path') || '';
   
   try {
-    const body = await request.json();
+    // тело передаётся как есть, в том числе пустое
+    const body = await request.text();
     const headers: Record<string, string> = {};
     
     // Копируем заголовки из запроса
     request.headers.forEach((value, key) => {
       // Исключаем некоторые заголовки, которые могут вызвать проблемы
-      if (!['host', 'connection', 'content-length'].includes(key.toLowerCase())) {
+      if (!['host', 'connection', 'content-length', ...FORWARDING_HEADERS, ...TRACE_HEADERS].includes(key.toLowerCase())) {
         headers[key] = value;
       }
     });
+    Object.assign(headers, traceHeaders(request), forwardedFor(request));
     
     // Добавляем Content-Type, если его нет
     if (!headers['content-type']) {
       headers['content-type'] = 'application/json';
     }
     
-    const response = await fetch(`${API_BASE_URL}/${path}`, {
+    const response = await fetch(backendUrl(path), {
       method: 'POST',
       headers,
-      body: JSON.stringify(body),
+      body: body || undefined,
       credentials: 'include',
     });
     
-    const data = await response.json();
-    
-    return NextResponse.json(data, {
-      status: response.status,
-      headers: {
-        'Cache-Control': 'no-store, max-age=0',
-      },
-    });
+    return passThrough(response);
   } catch (error) {
-    console.error('Proxy error:', error);
-    return NextResponse.json(
-      { error: 'Ошибка при выполнении запроса' },
-      { status: 500 }
-    );
+    return proxyProblem(request, error);
   }
 }
 
@@ -90,43 +172,34 @@ export async function PUT(request: NextRequest) {
   const path = searchParams.get('path') || '';
   
   try {
-    const body = await request.json();
+    // тело передаётся как есть, в том числе пустое
+    const body = await request.text();
     const headers: Record<string, string> = {};
     
     // Копируем заголовки из запроса
     request.headers.forEach((value, key) => {
       // Исключаем некоторые заголовки, которые могут вызвать проблемы
-      if (!['host', 'connection', 'content-length'].includes(key.toLowerCase())) {
+      if (!['host', 'connection', 'content-length', ...FORWARDING_HEADERS, ...TRACE_HEADERS].includes(key.toLowerCase())) {
         headers[key] = value;
       }
     });
+    Object.assign(headers, traceHeaders(request), forwardedFor(request));
     
     // Добавляем Content-Type, если его нет
     if (!headers['content-type']) {
       headers['content-type'] = 'application/json';
     }
     
-    const response = await fetch(`${API_BASE_URL}/${path}`, {
+    const response = await fetch(backendUrl(path), {
       method: 'PUT',
       headers,
-      body: JSON.stringify(body),
+      body: body || undefined,
       credentials: 'include',
     });
     
-    const data = await response.json();
-    
-    return NextResponse.json(data, {
-      status: response.status,
-      headers: {
-        'Cache-Control': 'no-store, max-age=0',
-      },
-    });
+    return passThrough(response);
   } catch (error) {
-    console.error('Proxy error:', error);
-    return NextResponse.json(
-      { error: 'Ошибка при выполнении запроса' },
-      { status: 500 }
-    );
+    return proxyProblem(request, error);
   }
 }
 
@@ -136,7 +209,7 @@ export async function OPTIONS(request: NextRequest) {
     headers: {
       'Access-Control-Allow-Origin': '*',
       'Access-Control-Allow-Methods': 'GET, POST, PUT, DELETE',
-      'Access-Control-Allow-Headers': 'Content-Type, Authorization',
+      'Access-Control-Allow-Headers': 'Content-Type, Authorization, traceparent, tracestate',
       'Cache-Control': 'no-store, max-age=0',
     },
   });
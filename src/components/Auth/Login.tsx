@@ -8,17 +8,28 @@ import styles from './Auth.module.css';
 
 export const Login = () => {
   const router = useRouter();
-  const { login, isLoading } = useAuth();
+  const { login, verifyTwoFactor, twoFactorRequired, isLoading } = useAuth();
   const [formData, setFormData] = useState({
     email: '',
     password: ''
   });
+  const [code, setCode] = useState('');
   const [formError, setFormError] = useState<string | null>(null);
 
   const handleSubmit = async (e: FormEvent) => {
     e.preventDefault();
     setFormError(null);
 
+    if (twoFactorRequired) {
+      try {
+        await verifyTwoFactor(code);
+        router.replace('/profile');
+      } catch (err) {
+        setFormError(err instanceof Error ? err.message : 'Произошла ошибка при входе');
+      }
+      return;
+    }
+
     if (!formData.email || !formData.password) {
       setFormError('Пожалуйста, заполните все поля');
       return;
@@ -28,7 +39,11 @@ export const Login = () => {
       console.log('Начало процесса входа');
       console.log('Данные для входа:', { email: formData.email, password: '***' });
 
-      await login(formData);
+      if (await login(formData)) {
+        // включена 2FA: форма переключится на ввод кода
+        setCode('');
+        return;
+      }
       console.log('Вход выполнен успешно, перенаправление на профиль');
       router.replace('/profile');
     } catch (err) {
@@ -56,35 +71,58 @@ export const Login = () => {
         <h2 className={styles.authTitle}>Вход в систему</h2>
         
         <form onSubmit={handleSubmit} className={styles.authForm}>
-          <div className={styles.formGroup}>
-            <label htmlFor="email" className={styles.label}>Email</label>
-            <input
-              type="email"
-              id="email"
-              name="email"
-              value={formData.email}
-              onChange={handleChange}
-              className={styles.input}
-              placeholder="Введите ваш email"
-              required
-              autoComplete="email"
-            />
-          </div>
+          {twoFactorRequired ? (
+            <div className={styles.formGroup}>
+              <label htmlFor="code" className={styles.label}>Код подтверждения</label>
+              <input
+                type="text"
+                id="code"
+                name="code"
+                value={code}
+                onChange={(e) => {
+                  setCode(e.target.value);
+                  setFormError(null);
+                }}
+                className={styles.input}
+                placeholder="Код из приложения или резервный код"
+                required
+                autoFocus
+                autoComplete="one-time-code"
+              />
+            </div>
+          ) : (
+            <>
+              <div className={styles.formGroup}>
+                <label htmlFor="email" className={styles.label}>Email</label>
+                <input
+                  type="email"
+                  id="email"
+                  name="email"
+                  value={formData.email}
+                  onChange={handleChange}
+                  className={styles.input}
+                  placeholder="Введите ваш email"
+                  required
+                  autoComplete="email"
+                />
+              </div>
 
-          <div className={styles.formGroup}>
-            <label htmlFor="password" className={styles.label}>Пароль</label>
-            <input
-              type="password"
-              id="password"
-              name="password"
-              value={formData.password}
-              onChange={handleChange}
-              className={styles.input}
-              placeholder="Введите пароль"
-              required
-              autoComplete="current-password"
-            />
-          </div>
+              <div className={styles.formGroup}>
+                <label htmlFor="password" className={styles.label}>Пароль</label>
+                <input
+                  type="password"
+                  id="password"
+                  name="password"
+                  value={formData.password}
+                  onChange={handleChange}
+                  className={styles.input}
+                  placeholder="Введите пароль"
+                  required
+                  autoComplete="current-password"
+                />
+              </div>
+            </>
+          )}
 
           {formError && (
             <div className={styles.error}>
@@ -95,9 +133,9 @@ export const Login = () => {
           <button 
             type="submit" 
             className={styles.submitButton}
-            disabled={isLoading || !formData.email || !formData.password}
+            disabled={isLoading || (twoFactorRequired ? !code.trim() : !formData.email || !formData.password)}
           >
-            {isLoading ? 'Вход...' : 'Войти'}
+            {isLoading ? 'Вход...' : twoFactorRequired ? 'Подтвердить' : 'Войти'}
           </button>
 
           <p className={styles.authLink}>
@@ -1,7 +1,7 @@
 'use client';
 
 import { useState, useEffect, ChangeEvent } from 'react';
-import { useAuth } from '@/hooks/useAuth';
+import { useAuth, authFetch } from '@/hooks/useAuth';
 import { useRouter } from 'next/navigation';
 import Link from 'next/link';
 import styles from './Profile.module.css';
@@ -9,6 +9,7 @@ import { Header } from '@/components/Header/Header';
 import { Footer } from '@/components/Footer/Footer';
 import { User, UpdateUserRequest } from '@/components/Header/types';
 import { useOrders, Order } from '@/hooks/useOrders';
+import { ApiError, readApiError } from '@/types/problem';
 
 interface OrderType {
   id: string;
@@ -48,31 +49,6 @@ interface SaveResponse {
   message: string;
 }
 
-// Проверяем, работаем ли мы на Vercel (HTTPS) или локально (HTTP)
-const isVercel = typeof window !== 'undefined' && window.location.hostname.includes('vercel.app');
-// Используем прокси для Vercel, чтобы обойти проблему Mixed Content
-const API_URL = isVercel 
-  ? '/api/proxy' // Это будет прокси через Next.js API routes
-  : 'http://92.246.76.171:8080/api';
-
-// Обновляем конфигурацию для fetch запросов
-const fetchConfig = {
-  credentials: 'include' as const,
-  headers: {
-    'Content-Type': 'application/json',
-    'Accept': 'application/json'
-  }
-};
-
-// Обновляем функцию получения заголовков
-const getAuthHeaders = () => {
-  const token = localStorage.getItem('authToken');
-  return {
-    ...fetchConfig.headers,
-    'Authorization': `Bearer ${token}`
-  };
-};
-
 export const Profile = () => {
   const { user, logout } = useAuth();
   const router = useRouter();
@@ -104,19 +80,7 @@ export const Profile = () => {
   useEffect(() => {
     const fetchUserData = async () => {
       try {
-        const token = localStorage.getItem('authToken');
-        if (!token) {
-          throw new Error('Не авторизован');
-        }
-
-        const url = isVercel 
-          ? `${API_URL}?path=profile` 
-          : `${API_URL}/profile`;
-
-        const response = await fetch(url, {
-          ...fetchConfig,
-          headers: getAuthHeaders()
-        });
+        const response = await authFetch('profile');
 
         if (!response.ok) {
           if (response.status === 401) {
@@ -164,11 +128,6 @@ export const Profile = () => {
     setSaveMessage('');
 
     try {
-      const token = localStorage.getItem('authToken');
-      if (!token) {
-        throw new Error('Не авторизован');
-      }
-
       const updateData: UpdateUserRequest = {
         name: userData.name,
         phone: userData.phone || null,
@@ -183,13 +142,8 @@ export const Profile = () => {
         language: userData.language || null,
       };
       
-      const url = isVercel 
-        ? `${API_URL}?path=profile` 
-        : `${API_URL}/profile`;
-
-      const response = await fetch(url, {
+      const response = await authFetch('profile', {
         method: 'PUT',
-        headers: getAuthHeaders(),
         body: JSON.stringify(updateData)
       });
 
@@ -198,8 +152,7 @@ export const Profile = () => {
           handleLogout();
           throw new Error('Сессия истекла. Пожалуйста, войдите снова');
         }
-        const errorData = await response.json();
-        throw new Error(errorData.message || 'Ошибка при сохранении изменений');
+        throw await readApiError(response, 'Ошибка при сохранении изменений');
       }
 
       const data = await response.json();
@@ -211,7 +164,12 @@ export const Profile = () => {
       setSaveMessage('Изменения успешно сохранены');
       setIsEditing(false);
     } catch (error) {
-      setSaveMessage(error instanceof Error ? error.message : 'Ошибка при сохранении изменений');
+      if (error instanceof ApiError && error.fields.length > 0) {
+        // сервер возвращает ошибки всех полей сразу
+        setSaveMessage(error.fields.map((field) => field.message).join('. '));
+      } else {
+        setSaveMessage(error instanceof Error ? error.message : 'Ошибка при сохранении изменений');
+      }
       if (error instanceof Error && error.message.includes('Сессия истекла')) {
         router.push('/login');
       }
@@ -1,6 +1,7 @@
 'use client';
 
 import { useState, useEffect } from 'react';
+import { readApiError } from '@/types/problem';
 
 // Проверяем, работаем ли мы на Vercel (HTTPS) или локально (HTTP)
 const isVercel = typeof window !== 'undefined' && window.location.hostname.includes('vercel.app');
@@ -44,14 +45,7 @@ const fetchWithTimeout = async (url: string, options: RequestInit, timeout = 300
 
     // Если ответ не OK, пробуем прочитать тело ответа
     if (!response.ok) {
-      let errorMessage = 'Ошибка сервера';
-      try {
-        const errorData = await response.json();
-        errorMessage = errorData.message || errorData.error || 'Неизвестная ошибка';
-      } catch (e) {
-        console.error('Не удалось прочитать тело ошибки:', e);
-      }
-      throw new Error(errorMessage);
+      throw await readApiError(response, 'Ошибка сервера');
     }
 
     return response;
@@ -75,13 +69,93 @@ const fetchWithTimeout = async (url: string, options: RequestInit, timeout = 300
   }
 };
 
-// Добавляем функцию для получения заголовков с токеном
-const getAuthHeaders = () => {
-  const token = localStorage.getItem('authToken');
-  return {
-    ...fetchConfig.headers,
-    'Authorization': `Bearer ${token}`
-  };
+const TOKEN_KEY = 'authToken';
+const REFRESH_TOKEN_KEY = 'refreshToken';
+
+const apiUrl = (path: string) => (isVercel ? `${API_URL}?path=${path}` : `${API_URL}/${path}`);
+
+// Ответ на вход, регистрацию и обновление токенов. Токен доступа живёт
+// 15 минут, после чего его обновляют по refresh-токену.
+interface AuthResponse {
+  token: string;
+  token_expires_at: string;
+  refresh_token: string;
+  refresh_expires_at: string;
+  user: User;
+}
+
+// При включённой 2FA вход возвращает challenge вместо токенов
+interface TwoFactorChallenge {
+  two_factor_required: true;
+  challenge_token: string;
+  expires_at: string;
+}
+
+const saveSession = (session: AuthResponse) => {
+  localStorage.setItem(TOKEN_KEY, session.token);
+  localStorage.setItem(REFRESH_TOKEN_KEY, session.refresh_token);
+};
+
+const clearSession = () => {
+  localStorage.removeItem(TOKEN_KEY);
+  localStorage.removeItem(REFRESH_TOKEN_KEY);
+};
+
+// refresh-токен одноразовый: параллельные запросы должны дождаться
+// одного обновления, иначе второй запрос отзовёт сеанс
+let refreshing: Promise<string | null> | null = null;
+
+const refreshAccessToken = (): Promise<string | null> => {
+  if (!refreshing) {
+    refreshing = (async () => {
+      const refreshToken = localStorage.getItem(REFRESH_TOKEN_KEY);
+      if (!refreshToken) {
+        return null;
+      }
+      try {
+        const response = await fetch(apiUrl('auth/refresh'), {
+          ...fetchConfig,
+          method: 'POST',
+          body: JSON.stringify({ refresh_token: refreshToken })
+        });
+        if (!response.ok) {
+          clearSession();
+          return null;
+        }
+        const session: AuthResponse = await response.json();
+        saveSession(session);
+        return session.token;
+      } catch (error) {
+        console.error('Ошибка при обновлении токена:', error);
+        return null;
+      } finally {
+        refreshing = null;
+      }
+    })();
+  }
+  return refreshing;
+};
+
+// authFetch выполняет запрос к API с токеном доступа. Если токен истёк,
+// он обновляется по refresh-токену и запрос повторяется один раз.
+export const authFetch = async (path: string, options: RequestInit = {}): Promise<Response> => {
+  const send = (token: string | null) =>
+    fetch(apiUrl(path), {
+      ...fetchConfig,
+      ...options,
+      headers: {
+        ...fetchConfig.headers,
+        ...(token ? { 'Authorization': `Bearer ${token}` } : {}),
+        ...options.headers
+      }
+    });
+
+  const response = await send(localStorage.getItem(TOKEN_KEY));
+  if (response.status !== 401) {
+    return response;
+  }
+  const token = await refreshAccessToken();
+  return token ? send(token) : response;
 };
 
 export interface User {
@@ -116,7 +190,10 @@ export interface RegisterData extends LoginData {
 
 export interface AuthContextType {
   user: User | null;
-  login: (data: LoginData) => Promise<void>;
+  // возвращает true, если для входа нужен код второго фактора
+  login: (data: LoginData) => Promise<boolean>;
+  verifyTwoFactor: (code: string) => Promise<void>;
+  twoFactorRequired: boolean;
   register: (data: RegisterData) => Promise<void>;
   logout: () => void;
   isAuthenticated: boolean;
@@ -160,30 +237,19 @@ export const useAuth = () => {
   const [isAuthenticated, setIsAuthenticated] = useState(false);
   const [isLoading, setIsLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
+  const [challengeToken, setChallengeToken] = useState<string | null>(null);
 
   useEffect(() => {
-    const token = localStorage.getItem('authToken');
-    if (token) {
-      fetchUser(token);
+    if (localStorage.getItem(TOKEN_KEY) || localStorage.getItem(REFRESH_TOKEN_KEY)) {
+      fetchUser();
     } else {
       setIsLoading(false);
     }
   }, []);
 
-  const fetchUser = async (token: string) => {
+  const fetchUser = async () => {
     try {
-      // Формируем URL в зависимости от того, используем ли мы прокси
-      const url = isVercel 
-        ? `${API_URL}?path=profile` 
-        : `${API_URL}/profile`;
-      
-      const response = await fetch(url, {
-        ...fetchConfig,
-        headers: {
-          ...fetchConfig.headers,
-          'Authorization': `Bearer ${token}`
-        }
-      });
+      const response = await authFetch('profile');
 
       if (!response.ok) {
         throw new Error('Ошибка при получении данных пользователя');
@@ -212,9 +278,11 @@ export const useAuth = () => {
       setIsAuthenticated(true);
     } catch (error) {
       console.error('Error fetching user:', error);
-      localStorage.removeItem('authToken');
+      clearSession();
       setIsAuthenticated(false);
       setUser(null);
+    } finally {
+      setIsLoading(false);
     }
   };
 
@@ -227,7 +295,7 @@ export const useAuth = () => {
     return password.length >= 8;
   };
 
-  const login = async (data: LoginData): Promise<void> => {
+  const login = async (data: LoginData): Promise<boolean> => {
     try {
       setIsLoading(true);
       setError(null);
@@ -240,27 +308,64 @@ export const useAuth = () => {
         throw new Error('Пароль должен содержать минимум 8 символов');
       }
 
-      const url = isVercel 
-        ? `${API_URL}?path=auth/login` 
-        : `${API_URL}/auth/login`;
-      
-      console.log('Отправка запроса на:', url);
-
-      const response = await fetch(url, {
+      const response = await fetch(apiUrl('auth/login'), {
         ...fetchConfig,
         method: 'POST',
         body: JSON.stringify(data)
       });
 
       if (!response.ok) {
-        const errorData = await response.json();
-        throw new Error(errorData.error || 'Ошибка при входе');
+        throw await readApiError(response, 'Ошибка при входе');
       }
 
-      const { token, user } = await response.json();
-      
-      localStorage.setItem('authToken', token);
-      setUser(user);
+      const result: AuthResponse | TwoFactorChallenge = await response.json();
+      if ('two_factor_required' in result) {
+        setChallengeToken(result.challenge_token);
+        return true;
+      }
+
+      saveSession(result);
+      setUser(result.user);
+      setIsAuthenticated(true);
+      return false;
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Произошла ошибка при входе');
+      throw err;
+    } finally {
+      setIsLoading(false);
+    }
+  };
+
+  // verifyTwoFactor завершает вход кодом из приложения-аутентификатора
+  // или резервным кодом
+  const verifyTwoFactor = async (code: string): Promise<void> => {
+    try {
+      setIsLoading(true);
+      setError(null);
+
+      if (!challengeToken) {
+        throw new Error('Сначала введите email и пароль');
+      }
+
+      const response = await fetch(apiUrl('auth/2fa/verify'), {
+        ...fetchConfig,
+        method: 'POST',
+        body: JSON.stringify({ challenge_token: challengeToken, code: code.trim() })
+      });
+
+      if (!response.ok) {
+        const apiError = await readApiError(response, 'Неверный код подтверждения');
+        if (apiError.code === 'invalid_challenge') {
+          // срок challenge истёк, нужно заново ввести пароль
+          setChallengeToken(null);
+        }
+        throw apiError;
+      }
+
+      const session: AuthResponse = await response.json();
+      saveSession(session);
+      setChallengeToken(null);
+      setUser(session.user);
       setIsAuthenticated(true);
     } catch (err) {
       setError(err instanceof Error ? err.message : 'Произошла ошибка при входе');
@@ -302,14 +407,8 @@ export const useAuth = () => {
         confirm_password: '***'
       });
 
-      const url = isVercel 
-        ? `${API_URL}?path=auth/register` 
-        : `${API_URL}/auth/register`;
-      
-      console.log('Отправка запроса на:', url);
-
       const response = await fetchWithTimeout(
-        url,
+        apiUrl('auth/register'),
         {
           ...fetchConfig,
           method: 'POST',
@@ -320,17 +419,13 @@ export const useAuth = () => {
 
       console.log('Успешный ответ от сервера');
       
-      const responseData = await response.json();
-      console.log('Полученные данные:', {
-        ...responseData,
-        token: responseData.token ? '***' : undefined
-      });
+      const responseData: AuthResponse = await response.json();
 
-      if (!responseData.token || !responseData.user) {
+      if (!responseData.token || !responseData.refresh_token || !responseData.user) {
         throw new Error('Сервер вернул неполные данные');
       }
 
-      localStorage.setItem('authToken', responseData.token);
+      saveSession(responseData);
       setUser(responseData.user);
       setIsAuthenticated(true);
       
@@ -346,7 +441,26 @@ export const useAuth = () => {
   };
 
   const logout = () => {
-    localStorage.removeItem('authToken');
+    const refreshToken = localStorage.getItem(REFRESH_TOKEN_KEY);
+    clearSession();
+    // сеанс на сервере отзываем в фоне, выход не должен зависеть от сети.
+    // Сервер находит сеанс по refresh-токену: токен доступа к этому
+    // моменту мог уже истечь.
+    if (refreshToken) {
+      fetch(apiUrl('auth/logout'), {
+        ...fetchConfig,
+        method: 'POST',
+        body: JSON.stringify({ refresh_token: refreshToken })
+      })
+        .then((response) => {
+          if (!response.ok) {
+            console.error('Сеанс не завершён на сервере:', response.status);
+          }
+        })
+        .catch((error) => {
+          console.error('Ошибка при завершении сеанса:', error);
+        });
+    }
     setUser(null);
     setIsAuthenticated(false);
   };
@@ -357,6 +471,8 @@ export const useAuth = () => {
     isLoading,
     error,
     login,
+    verifyTwoFactor,
+    twoFactorRequired: challengeToken !== null,
     register,
     logout
   };
@@ -0,0 +1,44 @@
+// Ошибка API в формате RFC 7807 (application/problem+json)
+export interface ProblemFieldError {
+  field: string;
+  code: string;
+  message: string;
+}
+
+export interface ProblemDetails {
+  type: string;
+  title: string;
+  status: number;
+  detail?: string;
+  instance?: string;
+  code: string;
+  errors?: ProblemFieldError[];
+  request_id?: string;
+}
+
+// ApiError сохраняет код ошибки, чтобы интерфейс мог ветвиться по нему,
+// а не по тексту сообщения
+export class ApiError extends Error {
+  code: string;
+  status: number;
+  fields: ProblemFieldError[];
+
+  constructor(message: string, status: number, code = 'unknown', fields: ProblemFieldError[] = []) {
+    super(message);
+    this.name = 'ApiError';
+    this.status = status;
+    this.code = code;
+    this.fields = fields;
+  }
+}
+
+export const readApiError = async (response: Response, fallback: string): Promise<ApiError> => {
+  try {
+    const data: Partial<ProblemDetails> & { message?: string; error?: string } = await response.json();
+    const message = data.detail || data.message || data.error || fallback;
+    return new ApiError(message, response.status, data.code, data.errors);
+  } catch (e) {
+    console.error('Не удалось прочитать тело ошибки:', e);
+    return new ApiError(fallback, response.status);
+  }
+};